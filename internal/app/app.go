@@ -26,6 +26,8 @@ type App struct {
 	httpServer *http.Server
 	logger     logger.Logger
 	pool       *pgxpool.Pool
+	services   *domain.Services
+	interval   time.Duration
 }
 
 func New() *App {
@@ -62,8 +64,10 @@ func New() *App {
 			WriteTimeout:   5 * time.Second,
 			ReadTimeout:    5 * time.Second,
 		},
-		logger: customLogger,
-		pool:   pool,
+		logger:   customLogger,
+		pool:     pool,
+		services: services,
+		interval: cfg.Scheduler.Interval,
 	}
 }
 
@@ -82,13 +86,48 @@ func (a *App) Run(ctx context.Context) {
 		}
 	}()
 
+	schedulerCtx, stopScheduler := context.WithCancel(ctx)
+	schedulerDone := make(chan struct{})
+
+	go func() {
+		defer close(schedulerDone)
+		a.runScheduler(schedulerCtx)
+	}()
+
 	a.logger.Info("App started successfully")
 
 	<-quit
 	a.logger.Info("Received shutdown signal, stopping app...")
+
+	stopScheduler()
+	<-schedulerDone
+
 	a.Stop(ctx)
 }
 
+// runScheduler puts the due recurring products on the lists until the context is done
+func (a *App) runScheduler(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			created, err := a.services.Recurring.CreateDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				a.logger.Error("Scheduler failed to add recurring products", slog.Any("error", err))
+				continue
+			}
+
+			if created > 0 {
+				a.logger.Info("Scheduler added recurring products", slog.Int("count", created))
+			}
+		}
+	}
+}
+
 func (a *App) Stop(ctx context.Context) {
 	a.logger.Info("App is shutting down...")
 
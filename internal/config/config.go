@@ -17,6 +17,7 @@ type Config struct {
 	HTTPServer HTTPServer
 	Postgres   Postgres
 	JWT        JWT
+	Scheduler  Scheduler
 }
 
 type HTTPServer struct {
@@ -38,6 +39,11 @@ type JWT struct {
 	RefreshExpire time.Duration `env:"JWT_REFRESH_EXPIRE"`
 }
 
+// Scheduler puts the recurring products on the lists, every replica runs it
+type Scheduler struct {
+	Interval time.Duration `env:"SCHEDULER_INTERVAL" env-default:"1m"`
+}
+
 func MustLoad() *Config {
 	var config Config
 
@@ -49,6 +55,11 @@ func MustLoad() *Config {
 		log.Fatalln("Error reading env", err)
 	}
 
+	// a ticker can't run with a zero or negative interval
+	if config.Scheduler.Interval <= 0 {
+		log.Fatalln("Error reading env", "SCHEDULER_INTERVAL must be positive")
+	}
+
 	return &config
 }
 
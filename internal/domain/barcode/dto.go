@@ -0,0 +1,34 @@
+package barcode
+
+// LookupDTO finds the product name of a code, the barcodes learned by GroupID or by any group
+// of the user when it's zero are looked up before the global ones
+type LookupDTO struct {
+	UserID  uint64
+	GroupID uint64
+	Code    string
+	Locales []string
+}
+
+type LearnDTO struct {
+	GroupID       uint64
+	UserID        uint64
+	Code          string
+	ProductNameID uint64
+}
+
+type BarcodeDTO struct {
+	Code          string  `json:"code" db:"code"`
+	ProductNameID uint64  `json:"product_name_id" db:"product_name_id"`
+	ProductName   string  `json:"product_name" db:"product_name"`
+	CategoryID    uint64  `json:"category_id" db:"category_id"`
+	Category      string  `json:"category" db:"category_name"`
+	DefaultUnit   string  `json:"default_unit" db:"default_unit"`
+	GroupID       *uint64 `json:"group_id" db:"group_id"`
+}
+
+// ImportedDTO counts the global barcodes added or replaced, Skipped are the lines with
+// an invalid code, an unknown or group product name, or a code repeated further down
+type ImportedDTO struct {
+	Imported int   `json:"imported"`
+	Skipped  []int `json:"skipped"`
+}
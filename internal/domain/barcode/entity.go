@@ -0,0 +1,20 @@
+package barcode
+
+import "time"
+
+// Barcode maps an EAN-13 code to a product name, GroupID is nil for a global barcode
+type Barcode struct {
+	BarcodeID     uint64
+	Code          string
+	ProductNameID uint64
+	GroupID       *uint64
+	CreatedBy     *uint64
+	CreatedAt     time.Time
+}
+
+// Mapping is a line of an imported file
+type Mapping struct {
+	Line          int
+	Code          string
+	ProductNameID uint64
+}
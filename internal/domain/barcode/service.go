@@ -0,0 +1,177 @@
+package barcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"github.com/tclutin/shoppinglist-api/pkg/gtin"
+	"slices"
+	"time"
+)
+
+type Repository interface {
+	Lookup(ctx context.Context, userID uint64, groupID uint64, code string, locales []string) (BarcodeDTO, error)
+	Learn(ctx context.Context, barcode Barcode) (uint64, error)
+	ImportGlobal(ctx context.Context, mappings []Mapping) ([]string, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type ProductRepository interface {
+	GetByProductNameId(ctx context.Context, productNameID uint64) (product.ProductName, error)
+}
+
+type Service struct {
+	repo        Repository
+	groupRepo   GroupRepository
+	memberRepo  MemberRepository
+	productRepo ProductRepository
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, productRepo ProductRepository) *Service {
+	return &Service{
+		repo:        repo,
+		groupRepo:   groupRepo,
+		memberRepo:  memberRepo,
+		productRepo: productRepo,
+	}
+}
+
+func (s *Service) Lookup(ctx context.Context, dto LookupDTO) (BarcodeDTO, error) {
+	code, ok := gtin.Normalize(dto.Code)
+	if !ok {
+		return BarcodeDTO{}, domainErr.ErrInvalidBarcode
+	}
+
+	if dto.GroupID != 0 {
+		group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return BarcodeDTO{}, domainErr.ErrGroupNotFound
+			}
+		}
+
+		_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return BarcodeDTO{}, domainErr.ErrMemberNotFound
+			}
+		}
+	}
+
+	barcode, err := s.repo.Lookup(ctx, dto.UserID, dto.GroupID, code, dto.Locales)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BarcodeDTO{}, domainErr.ErrBarcodeNotFound
+		}
+
+		return BarcodeDTO{}, fmt.Errorf("failed to lookup barcode: %w", err)
+	}
+
+	return barcode, nil
+}
+
+// Learn maps the code to a product name for the group, the barcode the group learned
+// before is replaced and a global one is shadowed
+func (s *Service) Learn(ctx context.Context, dto LearnDTO) (uint64, error) {
+	code, ok := gtin.Normalize(dto.Code)
+	if !ok {
+		return 0, domainErr.ErrInvalidBarcode
+	}
+
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	productName, err := s.productRepo.GetByProductNameId(ctx, dto.ProductNameID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrProductNotFound
+		}
+
+		return 0, fmt.Errorf("failed to get product name: %w", err)
+	}
+
+	if productName.GroupID != nil && *productName.GroupID != group.GroupID {
+		return 0, domainErr.ErrProductNotFound
+	}
+
+	return s.repo.Learn(ctx, Barcode{
+		Code:          code,
+		ProductNameID: productName.ProductNameID,
+		GroupID:       &group.GroupID,
+		CreatedBy:     &membr.UserID,
+		CreatedAt:     time.Now().UTC(),
+	})
+}
+
+// ImportGlobal adds or replaces the global barcodes of the mappings, a later line wins
+// over an earlier one with the same code
+func (s *Service) ImportGlobal(ctx context.Context, mappings []Mapping) (ImportedDTO, error) {
+	imported := ImportedDTO{Skipped: []int{}}
+
+	last := make(map[string]int, len(mappings))
+	valid := make([]Mapping, 0, len(mappings))
+
+	for _, mapping := range mappings {
+		code, ok := gtin.Normalize(mapping.Code)
+		if !ok {
+			imported.Skipped = append(imported.Skipped, mapping.Line)
+			continue
+		}
+
+		mapping.Code = code
+
+		if i, ok := last[code]; ok {
+			imported.Skipped = append(imported.Skipped, valid[i].Line)
+			valid[i] = mapping
+			continue
+		}
+
+		last[code] = len(valid)
+		valid = append(valid, mapping)
+	}
+
+	codes, err := s.repo.ImportGlobal(ctx, valid)
+	if err != nil {
+		return ImportedDTO{}, fmt.Errorf("failed to import barcodes: %w", err)
+	}
+
+	saved := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		saved[code] = true
+	}
+
+	// the product names that are unknown or belong to a group are left out by the repository
+	for _, mapping := range valid {
+		if !saved[mapping.Code] {
+			imported.Skipped = append(imported.Skipped, mapping.Line)
+		}
+	}
+
+	slices.Sort(imported.Skipped)
+	imported.Imported = len(codes)
+
+	return imported, nil
+}
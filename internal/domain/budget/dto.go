@@ -0,0 +1,67 @@
+package budget
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type CreateBudgetDTO struct {
+	GroupID    uint64
+	UserID     uint64
+	CategoryID *uint64
+	Amount     decimal.Decimal
+}
+
+type UpdateBudgetDTO struct {
+	GroupID  uint64
+	UserID   uint64
+	BudgetID uint64
+	Amount   decimal.Decimal
+}
+
+type BudgetUserDTO struct {
+	GroupID  uint64
+	UserID   uint64
+	BudgetID uint64
+}
+
+type GroupBudgetsDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+}
+
+type GroupAlertsDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+	Limit   int
+	Offset  int
+}
+
+// BudgetDTO is the budget with the spending of the current month in the currency of the group,
+// Percent can exceed 100
+type BudgetDTO struct {
+	BudgetID     uint64          `json:"budget_id" db:"budget_id"`
+	CategoryID   *uint64         `json:"category_id" db:"category_id"`
+	CategoryName *string         `json:"category_name" db:"category_name"`
+	Amount       decimal.Decimal `json:"amount" db:"amount"`
+	Spent        decimal.Decimal `json:"spent" db:"spent"`
+	Currency     string          `json:"currency" db:"currency"`
+	Remaining    decimal.Decimal `json:"remaining" db:"-"`
+	Percent      float64         `json:"percent" db:"-"`
+	PeriodStart  time.Time       `json:"period_start" db:"-"`
+	PeriodEnd    time.Time       `json:"period_end" db:"-"`
+}
+
+type AlertDTO struct {
+	BudgetAlertID uint64          `json:"budget_alert_id" db:"budget_alert_id"`
+	BudgetID      uint64          `json:"budget_id" db:"budget_id"`
+	CategoryID    *uint64         `json:"category_id" db:"category_id"`
+	CategoryName  *string         `json:"category_name" db:"category_name"`
+	PeriodStart   time.Time       `json:"period_start" db:"period_start"`
+	Threshold     int             `json:"threshold" db:"threshold"`
+	Spent         decimal.Decimal `json:"spent" db:"spent"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,28 @@
+package budget
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Thresholds are the percents of the budget that raise an alert once a month
+var Thresholds = []int{80, 100}
+
+// Budget limits monthly spending of the group, or of one category when CategoryID is set,
+// the amount is in the currency of the group
+type Budget struct {
+	BudgetID   uint64
+	GroupID    uint64
+	CategoryID *uint64
+	Amount     decimal.Decimal
+	CreatedAt  time.Time
+}
+
+type Alert struct {
+	BudgetID    uint64
+	PeriodStart time.Time
+	Threshold   int
+	Spent       decimal.Decimal
+	Amount      decimal.Decimal
+	CreatedAt   time.Time
+}
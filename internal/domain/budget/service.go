@@ -0,0 +1,256 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, budget Budget) (uint64, error)
+	GetById(ctx context.Context, budgetID uint64) (Budget, error)
+	Update(ctx context.Context, budget Budget) error
+	Delete(ctx context.Context, budgetID uint64) error
+	GetBudgets(ctx context.Context, groupID uint64, locales []string, from time.Time, to time.Time) ([]BudgetDTO, error)
+	CreateAlert(ctx context.Context, alert Alert) error
+	GetAlerts(ctx context.Context, groupID uint64, locales []string, limit int, offset int) ([]AlertDTO, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type ProductRepository interface {
+	GetCategoryById(ctx context.Context, categoryID uint64) (product.Category, error)
+}
+
+type Service struct {
+	repo        Repository
+	groupRepo   GroupRepository
+	memberRepo  MemberRepository
+	productRepo ProductRepository
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, productRepo ProductRepository) *Service {
+	return &Service{
+		repo:        repo,
+		groupRepo:   groupRepo,
+		memberRepo:  memberRepo,
+		productRepo: productRepo,
+	}
+}
+
+func (s *Service) CreateBudget(ctx context.Context, dto CreateBudgetDTO) (uint64, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	if membr.Role != "owner" {
+		return 0, domainErr.ErrAreNotOwner
+	}
+
+	if dto.CategoryID != nil {
+		_, err = s.productRepo.GetCategoryById(ctx, *dto.CategoryID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, domainErr.ErrCategoryNotFound
+			}
+
+			return 0, fmt.Errorf("failed to get category: %w", err)
+		}
+	}
+
+	budgetID, err := s.repo.Create(ctx, Budget{
+		GroupID:    group.GroupID,
+		CategoryID: dto.CategoryID,
+		Amount:     dto.Amount,
+		CreatedAt:  time.Now().UTC(),
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrBudgetAlreadyExists
+		}
+
+		return 0, fmt.Errorf("failed to create budget: %w", err)
+	}
+
+	return budgetID, nil
+}
+
+// GetBudgets returns the budgets of the group with the spending of the current month
+func (s *Service) GetBudgets(ctx context.Context, dto GroupBudgetsDTO) ([]BudgetDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	return s.getBudgets(ctx, group.GroupID, dto.Locales)
+}
+
+func (s *Service) UpdateBudget(ctx context.Context, dto UpdateBudgetDTO) error {
+	budget, err := s.getOwnBudget(ctx, BudgetUserDTO{
+		GroupID:  dto.GroupID,
+		UserID:   dto.UserID,
+		BudgetID: dto.BudgetID,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	budget.Amount = dto.Amount
+
+	return s.repo.Update(ctx, budget)
+}
+
+func (s *Service) DeleteBudget(ctx context.Context, dto BudgetUserDTO) error {
+	budget, err := s.getOwnBudget(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, budget.BudgetID)
+}
+
+// GetAlerts returns the alerts of the budgets of the group, the latest first
+func (s *Service) GetAlerts(ctx context.Context, dto GroupAlertsDTO) ([]AlertDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	return s.repo.GetAlerts(ctx, group.GroupID, dto.Locales, dto.Limit, dto.Offset)
+}
+
+// CheckThresholds raises an alert for every threshold the spending of the current month has reached,
+// every threshold of a budget alerts once a month
+func (s *Service) CheckThresholds(ctx context.Context, groupID uint64) error {
+	budgets, err := s.getBudgets(ctx, groupID, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, budget := range budgets {
+		for _, threshold := range Thresholds {
+			if budget.Spent.Mul(decimal.NewFromInt(100)).LessThan(budget.Amount.Mul(decimal.NewFromInt(int64(threshold)))) {
+				break
+			}
+
+			err = s.repo.CreateAlert(ctx, Alert{
+				BudgetID:    budget.BudgetID,
+				PeriodStart: budget.PeriodStart,
+				Threshold:   threshold,
+				Spent:       budget.Spent,
+				Amount:      budget.Amount,
+				CreatedAt:   time.Now().UTC(),
+			})
+
+			if err != nil {
+				return fmt.Errorf("failed to create alert: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) getBudgets(ctx context.Context, groupID uint64, locales []string) ([]BudgetDTO, error) {
+	from, to := currentPeriod(time.Now().UTC())
+
+	budgets, err := s.repo.GetBudgets(ctx, groupID, locales, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budgets: %w", err)
+	}
+
+	for i := range budgets {
+		budgets[i].Remaining = budgets[i].Amount.Sub(budgets[i].Spent)
+		budgets[i].Percent = budgets[i].Spent.Mul(decimal.NewFromInt(100)).Div(budgets[i].Amount).Round(2).InexactFloat64()
+		budgets[i].PeriodStart = from
+		budgets[i].PeriodEnd = to
+	}
+
+	return budgets, nil
+}
+
+// getOwnBudget returns the budget of the group if the user is its owner
+func (s *Service) getOwnBudget(ctx context.Context, dto BudgetUserDTO) (Budget, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Budget{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Budget{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	if membr.Role != "owner" {
+		return Budget{}, domainErr.ErrAreNotOwner
+	}
+
+	budget, err := s.repo.GetById(ctx, dto.BudgetID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Budget{}, domainErr.ErrBudgetNotFound
+		}
+
+		return Budget{}, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	if budget.GroupID != group.GroupID {
+		return Budget{}, domainErr.ErrBudgetNotFound
+	}
+
+	return budget, nil
+}
+
+// currentPeriod returns the bounds of the month of the time, the end is exclusive
+func currentPeriod(now time.Time) (time.Time, time.Time) {
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return from, from.AddDate(0, 1, 0)
+}
@@ -0,0 +1,13 @@
+package currency
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Rate is the price of one unit of the currency in rubles
+type Rate struct {
+	Currency  string          `json:"currency" db:"currency"`
+	Rate      decimal.Decimal `json:"rate" db:"rate"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
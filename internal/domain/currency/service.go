@@ -0,0 +1,35 @@
+package currency
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type Repository interface {
+	GetRates(ctx context.Context) ([]Rate, error)
+	SetRate(ctx context.Context, rate Rate) error
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+func (s *Service) GetRates(ctx context.Context) ([]Rate, error) {
+	return s.repo.GetRates(ctx)
+}
+
+// SetRate adds the currency or updates its rate
+func (s *Service) SetRate(ctx context.Context, currency string, rate decimal.Decimal) error {
+	return s.repo.SetRate(ctx, Rate{
+		Currency:  currency,
+		Rate:      rate,
+		UpdatedAt: time.Now().UTC(),
+	})
+}
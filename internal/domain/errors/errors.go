@@ -18,9 +18,15 @@ var (
 	// ErrRefreshTokenExpired AuthService
 	ErrRefreshTokenExpired = errors.New("refresh token expired")
 
+	// ErrAreNotAdmin AuthService
+	ErrAreNotAdmin = errors.New("you aren't admin")
+
 	// ErrUserNotFound UserService
 	ErrUserNotFound = errors.New("user not found")
 
+	// ErrInvalidLocale UserService
+	ErrInvalidLocale = errors.New("invalid locale")
+
 	// ErrInvalidCode GroupService
 	ErrInvalidCode = errors.New("invalid code")
 
@@ -44,4 +50,106 @@ var (
 
 	// ErrProductNotFound ProductService
 	ErrProductNotFound = errors.New("product not found")
+
+	// ErrInvalidUnit ProductService
+	ErrInvalidUnit = errors.New("invalid unit")
+
+	// ErrCategoryNotFound ProductService
+	ErrCategoryNotFound = errors.New("category not found")
+
+	// ErrCategoryInUse ProductService
+	ErrCategoryInUse = errors.New("category has product names")
+
+	// ErrProductNameInUse ProductService
+	ErrProductNameInUse = errors.New("product name is in use")
+
+	// ErrProductClosed GroupService
+	ErrProductClosed = errors.New("product is already bought")
+
+	// ErrClaimedByAnotherMember GroupService
+	ErrClaimedByAnotherMember = errors.New("product is claimed by another member")
+
+	// ErrTripNotFound TripService
+	ErrTripNotFound = errors.New("trip not found")
+
+	// ErrProductUnavailable TripService
+	ErrProductUnavailable = errors.New("product is not open in the group")
+
+	// ErrBudgetNotFound BudgetService
+	ErrBudgetNotFound = errors.New("budget not found")
+
+	// ErrBudgetAlreadyExists BudgetService
+	ErrBudgetAlreadyExists = errors.New("budget already exists")
+
+	// ErrUnknownCurrency CurrencyService
+	ErrUnknownCurrency = errors.New("unknown currency")
+
+	// ErrRecurringProductNotFound RecurringService
+	ErrRecurringProductNotFound = errors.New("recurring product not found")
+
+	// ErrInvalidSchedule RecurringService
+	ErrInvalidSchedule = errors.New("invalid schedule")
+
+	// ErrTemplateNotFound TemplateService
+	ErrTemplateNotFound = errors.New("template not found")
+
+	// ErrTemplateEmpty TemplateService
+	ErrTemplateEmpty = errors.New("template has no items")
+
+	// ErrPantryItemNotFound PantryService
+	ErrPantryItemNotFound = errors.New("pantry item not found")
+
+	// ErrRestockQuantityRequired PantryService
+	ErrRestockQuantityRequired = errors.New("restock quantity is required with a low stock threshold")
+
+	// ErrRecipeNotFound RecipeService
+	ErrRecipeNotFound = errors.New("recipe not found")
+
+	// ErrMealPlanEntryNotFound MealPlanService
+	ErrMealPlanEntryNotFound = errors.New("meal plan entry not found")
+
+	// ErrMealPlanEntryEmpty MealPlanService
+	ErrMealPlanEntryEmpty = errors.New("meal plan entry needs a recipe or text")
+
+	// ErrInvalidPeriod MealPlanService
+	ErrInvalidPeriod = errors.New("invalid period")
+
+	// ErrStoreNotFound StoreService
+	ErrStoreNotFound = errors.New("store not found")
+
+	// ErrCategoryInSeveralSections StoreService
+	ErrCategoryInSeveralSections = errors.New("category is in several sections of the store")
+
+	// ErrInvalidBarcode BarcodeService
+	ErrInvalidBarcode = errors.New("barcode is not a valid EAN-13 or UPC-A code")
+
+	// ErrBarcodeNotFound BarcodeService
+	ErrBarcodeNotFound = errors.New("barcode not found")
+
+	// ErrThresholdsNotChecked BudgetService
+	ErrThresholdsNotChecked = errors.New("budget thresholds are not checked")
+
+	// ErrCannotSettleWithYourself ExpenseService
+	ErrCannotSettleWithYourself = errors.New("can not settle with yourself")
+
+	// ErrInvalidCursor ProductService
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrCannotMergeIntoItself ProductService
+	ErrCannotMergeIntoItself = errors.New("can not merge into itself")
+
+	// ErrCannotMoveNextToItself GroupService
+	ErrCannotMoveNextToItself = errors.New("can not move a product next to itself")
+
+	// ErrForeignProductName ProductService
+	ErrForeignProductName = errors.New("product name belongs to another group")
+
+	// ErrProductNameAlreadyExists GroupService
+	ErrProductNameAlreadyExists = errors.New("product name already exists")
+
+	// ErrProductNameEmpty GroupService
+	ErrProductNameEmpty = errors.New("product name is empty")
+
+	// ErrCategoryRequired GroupService
+	ErrCategoryRequired = errors.New("category is required for a new product name")
 )
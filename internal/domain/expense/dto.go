@@ -0,0 +1,71 @@
+package expense
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type GroupUserDTO struct {
+	GroupID uint64
+	UserID  uint64
+}
+
+type CreateSettlementDTO struct {
+	GroupID    uint64
+	UserID     uint64
+	ToMemberID uint64
+	Amount     decimal.Decimal
+	// Currency is the currency of the group when empty
+	Currency string
+}
+
+type UpdateShareDTO struct {
+	GroupID  uint64
+	UserID   uint64
+	MemberID uint64
+	Share    float64
+}
+
+type UpdateExclusionsDTO struct {
+	GroupID   uint64
+	UserID    uint64
+	ProductID uint64
+	MemberIDs []uint64
+}
+
+// BalanceDTO is positive when the group owes the user and negative when the user owes the group
+type BalanceDTO struct {
+	UserID   uint64          `json:"user_id"`
+	Username string          `json:"username"`
+	Paid     decimal.Decimal `json:"paid"`
+	Owed     decimal.Decimal `json:"owed"`
+	Balance  decimal.Decimal `json:"balance"`
+}
+
+type TransferDTO struct {
+	FromUserID uint64          `json:"from_user_id"`
+	From       string          `json:"from"`
+	ToUserID   uint64          `json:"to_user_id"`
+	To         string          `json:"to"`
+	Amount     decimal.Decimal `json:"amount"`
+}
+
+// BalancesDTO is in the currency of the group
+type BalancesDTO struct {
+	Currency  string        `json:"currency"`
+	Balances  []BalanceDTO  `json:"balances"`
+	Transfers []TransferDTO `json:"transfers"`
+}
+
+type SettlementDTO struct {
+	SettlementID uint64          `json:"settlement_id" db:"settlement_id"`
+	FromUserID   uint64          `json:"from_user_id" db:"from_user_id"`
+	From         string          `json:"from" db:"from_username"`
+	ToUserID     uint64          `json:"to_user_id" db:"to_user_id"`
+	To           string          `json:"to" db:"to_username"`
+	Amount       decimal.Decimal `json:"amount" db:"amount"`
+	Currency     string          `json:"currency" db:"currency"`
+	// GroupAmount is the amount in the currency of the group
+	GroupAmount decimal.Decimal `json:"group_amount" db:"group_amount"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,47 @@
+package expense
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	// SplitEqual splits every expense equally between the members
+	SplitEqual = "equal"
+
+	// SplitShare splits every expense in proportion to the shares of the members
+	SplitShare = "share"
+)
+
+// Participant is a user the balance is computed for, the former members
+// only get back what they paid and are not charged for new expenses
+type Participant struct {
+	UserID   uint64
+	Username string
+	Share    float64
+	IsMember bool
+}
+
+// Expense is money paid by a member for the group in the currency of the group. ProductID
+// is nil for the part of a trip total that isn't covered by the prices of its products
+type Expense struct {
+	ProductID *uint64
+	PayerID   uint64
+	Amount    decimal.Decimal
+}
+
+// Exclusion means that the user doesn't pay for the product
+type Exclusion struct {
+	ProductID uint64
+	UserID    uint64
+}
+
+type Settlement struct {
+	SettlementID uint64
+	GroupID      uint64
+	FromUserID   uint64
+	ToUserID     uint64
+	Amount       decimal.Decimal
+	Currency     string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,432 @@
+package expense
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/tclutin/shoppinglist-api/internal/domain/currency"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"math"
+	"slices"
+	"time"
+)
+
+type Repository interface {
+	GetParticipants(ctx context.Context, groupID uint64) ([]Participant, error)
+	GetExpenses(ctx context.Context, groupID uint64) ([]Expense, error)
+	GetExclusions(ctx context.Context, groupID uint64) ([]Exclusion, error)
+	UpdateExclusions(ctx context.Context, productID uint64, userIDs []uint64) error
+	CreateSettlement(ctx context.Context, settlement Settlement) (uint64, error)
+	GetSettlements(ctx context.Context, groupID uint64) ([]SettlementDTO, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+	GetByMemberAndGroupId(ctx context.Context, memberID uint64, groupID uint64) (member.Member, error)
+	UpdateShare(ctx context.Context, memberID uint64, share float64) error
+}
+
+type CurrencyRepository interface {
+	GetRate(ctx context.Context, code string) (currency.Rate, error)
+}
+
+type ProductRepository interface {
+	GetById(ctx context.Context, productID uint64) (product.Product, error)
+}
+
+type Service struct {
+	repo         Repository
+	groupRepo    GroupRepository
+	memberRepo   MemberRepository
+	productRepo  ProductRepository
+	currencyRepo CurrencyRepository
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, productRepo ProductRepository, currencyRepo CurrencyRepository) *Service {
+	return &Service{
+		repo:         repo,
+		groupRepo:    groupRepo,
+		memberRepo:   memberRepo,
+		productRepo:  productRepo,
+		currencyRepo: currencyRepo,
+	}
+}
+
+// GetBalances computes what every user paid and owes for the bought products and trips of
+// the group, taking the settlements into account, and the transfers that settle the group up
+func (s *Service) GetBalances(ctx context.Context, dto GroupUserDTO) (BalancesDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BalancesDTO{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BalancesDTO{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	participants, err := s.repo.GetParticipants(ctx, group.GroupID)
+	if err != nil {
+		return BalancesDTO{}, fmt.Errorf("failed to get participants: %w", err)
+	}
+
+	expenses, err := s.repo.GetExpenses(ctx, group.GroupID)
+	if err != nil {
+		return BalancesDTO{}, fmt.Errorf("failed to get expenses: %w", err)
+	}
+
+	exclusions, err := s.repo.GetExclusions(ctx, group.GroupID)
+	if err != nil {
+		return BalancesDTO{}, fmt.Errorf("failed to get exclusions: %w", err)
+	}
+
+	settlements, err := s.repo.GetSettlements(ctx, group.GroupID)
+	if err != nil {
+		return BalancesDTO{}, fmt.Errorf("failed to get settlements: %w", err)
+	}
+
+	balances := computeBalances(group.SplitRule, participants, expenses, exclusions, settlements)
+
+	return BalancesDTO{
+		Currency:  group.Currency,
+		Balances:  balances,
+		Transfers: settleUp(balances),
+	}, nil
+}
+
+// CreateSettlement records that the user paid the amount to the member
+func (s *Service) CreateSettlement(ctx context.Context, dto CreateSettlementDTO) (uint64, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	receiver, err := s.memberRepo.GetByMemberAndGroupId(ctx, dto.ToMemberID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	if receiver.UserID == membr.UserID {
+		return 0, domainErr.ErrCannotSettleWithYourself
+	}
+
+	if dto.Currency == "" {
+		dto.Currency = group.Currency
+	}
+
+	_, err = s.currencyRepo.GetRate(ctx, dto.Currency)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrUnknownCurrency
+		}
+
+		return 0, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	return s.repo.CreateSettlement(ctx, Settlement{
+		GroupID:    group.GroupID,
+		FromUserID: membr.UserID,
+		ToUserID:   receiver.UserID,
+		Amount:     dto.Amount,
+		Currency:   dto.Currency,
+		CreatedAt:  time.Now().UTC(),
+	})
+}
+
+func (s *Service) GetSettlements(ctx context.Context, dto GroupUserDTO) ([]SettlementDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	return s.repo.GetSettlements(ctx, group.GroupID)
+}
+
+// UpdateShare sets the weight of the member for the "share" split rule, only for the owner
+func (s *Service) UpdateShare(ctx context.Context, dto UpdateShareDTO) error {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrGroupNotFound
+		}
+	}
+
+	owner, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrMemberNotFound
+		}
+	}
+
+	if owner.Role != "owner" {
+		return domainErr.ErrAreNotOwner
+	}
+
+	membr, err := s.memberRepo.GetByMemberAndGroupId(ctx, dto.MemberID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrMemberNotFound
+		}
+	}
+
+	return s.memberRepo.UpdateShare(ctx, membr.MemberID, dto.Share)
+}
+
+// UpdateExclusions replaces the members who don't pay for the product
+func (s *Service) UpdateExclusions(ctx context.Context, dto UpdateExclusionsDTO) error {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrMemberNotFound
+		}
+	}
+
+	product, err := s.productRepo.GetById(ctx, dto.ProductID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrProductNotFound
+		}
+
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if product.GroupID != group.GroupID {
+		return domainErr.ErrProductNotFound
+	}
+
+	userIDs := make([]uint64, 0, len(dto.MemberIDs))
+	for _, memberID := range dto.MemberIDs {
+		membr, err := s.memberRepo.GetByMemberAndGroupId(ctx, memberID, group.GroupID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domainErr.ErrMemberNotFound
+			}
+
+			return fmt.Errorf("failed to get member: %w", err)
+		}
+
+		userIDs = append(userIDs, membr.UserID)
+	}
+
+	return s.repo.UpdateExclusions(ctx, product.ProductID, userIDs)
+}
+
+// computeBalances works in cents, so that the balances of the group always sum up to zero
+func computeBalances(rule string, participants []Participant, expenses []Expense, exclusions []Exclusion, settlements []SettlementDTO) []BalanceDTO {
+	paid := make(map[uint64]int64, len(participants))
+	owed := make(map[uint64]int64, len(participants))
+	sent := make(map[uint64]int64, len(participants))
+
+	excluded := make(map[Exclusion]bool, len(exclusions))
+	for _, exclusion := range exclusions {
+		excluded[exclusion] = true
+	}
+
+	for _, expense := range expenses {
+		amount := toCents(expense.Amount)
+		paid[expense.PayerID] += amount
+
+		var debtors []Participant
+		for _, participant := range participants {
+			if !participant.IsMember {
+				continue
+			}
+
+			if expense.ProductID != nil && excluded[Exclusion{ProductID: *expense.ProductID, UserID: participant.UserID}] {
+				continue
+			}
+
+			debtors = append(debtors, participant)
+		}
+
+		// nobody to split with, the payer bears the expense alone
+		if len(debtors) == 0 {
+			owed[expense.PayerID] += amount
+			continue
+		}
+
+		for userID, part := range split(amount, debtors, rule) {
+			owed[userID] += part
+		}
+	}
+
+	for _, settlement := range settlements {
+		amount := toCents(settlement.GroupAmount)
+		sent[settlement.FromUserID] += amount
+		sent[settlement.ToUserID] -= amount
+	}
+
+	balances := make([]BalanceDTO, 0, len(participants))
+	for _, participant := range participants {
+		userID := participant.UserID
+
+		balances = append(balances, BalanceDTO{
+			UserID:   userID,
+			Username: participant.Username,
+			Paid:     fromCents(paid[userID]),
+			Owed:     fromCents(owed[userID]),
+			Balance:  fromCents(paid[userID] - owed[userID] + sent[userID]),
+		})
+	}
+
+	return balances
+}
+
+// split divides the amount between the debtors by the rule, the cents left after
+// rounding go to the first debtors, so the parts always sum up to the amount
+func split(amount int64, debtors []Participant, rule string) map[uint64]int64 {
+	weights := make([]float64, len(debtors))
+	total := 0.0
+
+	for i, debtor := range debtors {
+		weights[i] = 1
+		if rule == SplitShare {
+			weights[i] = debtor.Share
+		}
+
+		total += weights[i]
+	}
+
+	// nobody has a share, fall back to the equal split
+	if total == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+
+		total = float64(len(weights))
+	}
+
+	sign := int64(1)
+	if amount < 0 {
+		sign, amount = -1, -amount
+	}
+
+	parts := make(map[uint64]int64, len(debtors))
+	left := amount
+
+	for i, debtor := range debtors {
+		part := int64(math.Floor(float64(amount) * weights[i] / total))
+		parts[debtor.UserID] = part
+		left -= part
+	}
+
+	for i := 0; left > 0; i = (i + 1) % len(debtors) {
+		if weights[i] == 0 {
+			continue
+		}
+
+		parts[debtors[i].UserID]++
+		left--
+	}
+
+	for userID := range parts {
+		parts[userID] *= sign
+	}
+
+	return parts
+}
+
+// settleUp pairs the biggest debtor with the biggest creditor until everybody is settled,
+// which takes at most one transfer less than there are users with a non-zero balance
+func settleUp(balances []BalanceDTO) []TransferDTO {
+	type position struct {
+		balance BalanceDTO
+		cents   int64
+	}
+
+	var debtors, creditors []position
+	for _, balance := range balances {
+		cents := toCents(balance.Balance)
+
+		if cents < 0 {
+			debtors = append(debtors, position{balance: balance, cents: -cents})
+		}
+
+		if cents > 0 {
+			creditors = append(creditors, position{balance: balance, cents: cents})
+		}
+	}
+
+	byAmount := func(a, b position) int {
+		return cmp.Or(cmp.Compare(b.cents, a.cents), cmp.Compare(a.balance.UserID, b.balance.UserID))
+	}
+
+	slices.SortFunc(debtors, byAmount)
+	slices.SortFunc(creditors, byAmount)
+
+	transfers := make([]TransferDTO, 0)
+
+	for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+		amount := min(debtors[i].cents, creditors[j].cents)
+
+		transfers = append(transfers, TransferDTO{
+			FromUserID: debtors[i].balance.UserID,
+			From:       debtors[i].balance.Username,
+			ToUserID:   creditors[j].balance.UserID,
+			To:         creditors[j].balance.Username,
+			Amount:     fromCents(amount),
+		})
+
+		debtors[i].cents -= amount
+		creditors[j].cents -= amount
+
+		if debtors[i].cents == 0 {
+			i++
+		}
+
+		if creditors[j].cents == 0 {
+			j++
+		}
+	}
+
+	return transfers
+}
+
+func toCents(amount decimal.Decimal) int64 {
+	return amount.Shift(2).Round(0).IntPart()
+}
+
+func fromCents(cents int64) decimal.Decimal {
+	return decimal.New(cents, -2)
+}
@@ -1,5 +1,11 @@
 package group
 
+import (
+	"github.com/shopspring/decimal"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"time"
+)
+
 type CreateGroupDTO struct {
 	OwnerID     uint64
 	Name        string
@@ -16,6 +22,13 @@ type GroupUserDTO struct {
 	UserID  uint64
 }
 
+type GroupProductsDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+	Filter  product.ProductFilter
+}
+
 type KickMemberDTO struct {
 	GroupID  uint64
 	UserID   uint64
@@ -23,18 +36,60 @@ type KickMemberDTO struct {
 }
 
 type GroupDTO struct {
-	GroupID     uint64 `json:"groupID" db:"group_id"`
-	Name        string `json:"name" db:"name"`
-	Description string `json:"description" db:"description"`
-	Code        string `json:"code" db:"code"`
+	GroupID         uint64 `json:"groupID" db:"group_id"`
+	Name            string `json:"name" db:"name"`
+	Description     string `json:"description" db:"description"`
+	Code            string `json:"code" db:"code"`
+	MergeDuplicates bool   `json:"mergeDuplicates" db:"merge_duplicates"`
+	SplitRule       string `json:"splitRule" db:"split_rule"`
+	Currency        string `json:"currency" db:"currency"`
 }
 
 type CreateProductDTO struct {
 	UserID        uint64
 	GroupID       uint64
 	ProductNameID uint64
-	Quantity      int
+	ProductName   string
+	CategoryID    uint64
+	Quantity      float64
+	Unit          string
 	AddedBy       uint64
+	Note          string
+	Priority      string
+	NeededBy      *time.Time
+	// Merge overrides the merge_duplicates setting of the group when set
+	Merge *bool
+}
+
+type AddedProductDTO struct {
+	ProductID uint64
+	// EstimatedPrice is computed from the prices the group paid before in the currency of the group,
+	// nil when there are none
+	EstimatedPrice *decimal.Decimal
+	Currency       string
+}
+
+type CreateCatalogProductDTO struct {
+	UserID      uint64
+	GroupID     uint64
+	CategoryID  uint64
+	Name        string
+	DefaultUnit string
+}
+
+// UpdateSettingsDTO changes only the given settings
+type UpdateSettingsDTO struct {
+	GroupID         uint64
+	UserID          uint64
+	MergeDuplicates *bool
+	SplitRule       string
+	Currency        string
+}
+
+type ProductRequestsDTO struct {
+	ProductID uint64
+	GroupID   uint64
+	UserID    uint64
 }
 
 type RemoveProductDTO struct {
@@ -47,7 +102,62 @@ type UpdateProductDTO struct {
 	ProductID uint64
 	GroupID   uint64
 	UserID    uint64
-	Price     *float64
-	Quantity  int
-	Status    string
+	Price     *decimal.Decimal
+	// Currency of the price, the current one is kept when empty
+	Currency string
+	Quantity float64
+	Unit     string
+	Status   string
+	// Note, Priority and NeededBy are left as they are when empty, ClearNeededBy removes the date
+	Note          *string
+	Priority      string
+	NeededBy      *time.Time
+	ClearNeededBy bool
+	// Force closes the product even if another member has claimed it
+	Force bool
+	// ToPantry adds the bought product to the pantry of the group, ExpiresAt is the expiry date of the stock
+	ToPantry  bool
+	ExpiresAt *time.Time
+}
+
+// MoveProductDTO puts the product right before Before or right after After, one of them is set
+type MoveProductDTO struct {
+	ProductID uint64
+	GroupID   uint64
+	UserID    uint64
+	Before    uint64
+	After     uint64
+}
+
+type ClaimProductDTO struct {
+	ProductID uint64
+	GroupID   uint64
+	UserID    uint64
+	// MemberID is the member to assign the product to, the caller when zero
+	MemberID uint64
+}
+
+type ParseProductsDTO struct {
+	UserID  uint64
+	GroupID uint64
+	Lines   []string
+	Create  bool
+	Locales []string
+}
+
+type ProductSuggestionDTO struct {
+	ProductNameID uint64  `json:"product_name_id"`
+	CategoryID    uint64  `json:"category_id"`
+	Name          string  `json:"name"`
+	Confidence    float64 `json:"confidence"`
+}
+
+type ParsedProductDTO struct {
+	Line           string                 `json:"line"`
+	Quantity       float64                `json:"quantity"`
+	Unit           string                 `json:"unit"`
+	Name           string                 `json:"name"`
+	Suggestions    []ProductSuggestionDTO `json:"suggestions"`
+	ProductID      *uint64                `json:"product_id"`
+	EstimatedPrice *decimal.Decimal       `json:"estimated_price"`
 }
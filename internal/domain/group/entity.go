@@ -3,9 +3,12 @@ package group
 import "time"
 
 type Group struct {
-	GroupID     uint64
-	Name        string
-	Description string
-	Code        string
-	CreatedAt   time.Time
+	GroupID         uint64
+	Name            string
+	Description     string
+	Code            string
+	CreatedAt       time.Time
+	MergeDuplicates bool
+	SplitRule       string
+	Currency        string
 }
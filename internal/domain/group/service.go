@@ -1,23 +1,67 @@
 package group
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/tclutin/shoppinglist-api/internal/domain/currency"
 	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
 	"github.com/tclutin/shoppinglist-api/internal/domain/member"
 	"github.com/tclutin/shoppinglist-api/internal/domain/product"
 	"github.com/tclutin/shoppinglist-api/pkg/hash"
+	"github.com/tclutin/shoppinglist-api/pkg/lineparser"
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+	"math"
+	"slices"
+	"strings"
 	"time"
 )
 
+const (
+	// suggestionsLimit is how many catalog matches are offered for a parsed line
+	suggestionsLimit = 3
+
+	// autoCreateConfidence is the lowest confidence of the best match to add the parsed line without confirmation
+	autoCreateConfidence = 0.6
+)
+
 type ProductService interface {
 	Create(ctx context.Context, product product.Product) (uint64, error)
+	CreateOrMerge(ctx context.Context, product product.Product) (uint64, bool, error)
+	Dedupe(ctx context.Context, groupID uint64) (int, error)
+	GetProductRequests(ctx context.Context, productID uint64) ([]product.ProductRequestDTO, error)
 	Update(ctx context.Context, product product.Product) error
+	UpdateToPantry(ctx context.Context, product product.Product, expiresAt *time.Time) error
+	Assign(ctx context.Context, productID uint64, assignedTo *uint64, claimedAt *time.Time) error
+	Move(ctx context.Context, productID uint64, anchorID uint64, after bool) error
 	GetByProductNameId(ctx context.Context, productNameID uint64) (product.ProductName, error)
+	GetProductNameByName(ctx context.Context, groupID uint64, name string) (product.ProductName, error)
+	CreateProductName(ctx context.Context, productName product.ProductName) (uint64, error)
+	GetGroupProductNames(ctx context.Context, groupID uint64) ([]product.ProductName, error)
+	GetCategoryById(ctx context.Context, categoryID uint64) (product.Category, error)
 	RemoveProduct(ctx context.Context, productID uint64) error
 	GetById(ctx context.Context, productID uint64) (product.Product, error)
-	GetGroupProducts(ctx context.Context, groupID uint64) ([]product.ProductDTO, error)
+	GetGroupProducts(ctx context.Context, groupID uint64, locales []string, filter product.ProductFilter) (product.ProductPageDTO, error)
+	Search(ctx context.Context, dto product.SearchDTO) ([]product.SearchResultDTO, error)
+}
+
+type PriceService interface {
+	Estimate(ctx context.Context, groupID uint64, productNameID uint64, quantity float64, productUnit string) (*decimal.Decimal, error)
+}
+
+type BudgetService interface {
+	CheckThresholds(ctx context.Context, groupID uint64) error
+}
+
+type CurrencyRepository interface {
+	GetRate(ctx context.Context, code string) (currency.Rate, error)
+}
+
+type StoreRepository interface {
+	GetStoreGroupId(ctx context.Context, storeID uint64) (uint64, error)
 }
 
 type MemberRepository interface {
@@ -34,17 +78,26 @@ type Repository interface {
 	Delete(ctx context.Context, groupID uint64) error
 	GetById(ctx context.Context, groupID uint64) (Group, error)
 	GetByCode(ctx context.Context, code string) (Group, error)
+	UpdateSettings(ctx context.Context, group Group) error
 }
 
 type Service struct {
 	productService ProductService
+	priceService   PriceService
+	budgetService  BudgetService
+	currencyRepo   CurrencyRepository
+	storeRepo      StoreRepository
 	repo           Repository
 	memberRepo     MemberRepository
 }
 
-func NewService(repo Repository, memberRepo MemberRepository, productService ProductService) *Service {
+func NewService(repo Repository, memberRepo MemberRepository, productService ProductService, priceService PriceService, budgetService BudgetService, currencyRepo CurrencyRepository, storeRepo StoreRepository) *Service {
 	return &Service{
 		productService: productService,
+		priceService:   priceService,
+		budgetService:  budgetService,
+		currencyRepo:   currencyRepo,
+		storeRepo:      storeRepo,
 		repo:           repo,
 		memberRepo:     memberRepo,
 	}
@@ -214,24 +267,33 @@ func (s *Service) KickMember(ctx context.Context, dto KickMemberDTO) error {
 }
 
 // TODO: needs to refactor
-func (s *Service) AddProduct(ctx context.Context, dto CreateProductDTO) (uint64, error) {
+func (s *Service) AddProduct(ctx context.Context, dto CreateProductDTO) (AddedProductDTO, error) {
 	group, err := s.repo.GetById(ctx, dto.GroupID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return 0, domainErr.ErrGroupNotFound
+			return AddedProductDTO{}, domainErr.ErrGroupNotFound
 		}
 	}
 
 	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return 0, domainErr.ErrMemberNotFound
+			return AddedProductDTO{}, domainErr.ErrMemberNotFound
 		}
 	}
 
-	productName, err := s.productService.GetByProductNameId(ctx, dto.ProductNameID)
+	productName, err := s.resolveProductName(ctx, group.GroupID, dto)
 	if err != nil {
-		return 0, err
+		return AddedProductDTO{}, err
+	}
+
+	productUnit := dto.Unit
+	if productUnit == "" {
+		productUnit = productName.DefaultUnit
+	}
+
+	if !unit.IsValid(productUnit) {
+		return AddedProductDTO{}, domainErr.ErrInvalidUnit
 	}
 
 	product := product.Product{
@@ -240,12 +302,267 @@ func (s *Service) AddProduct(ctx context.Context, dto CreateProductDTO) (uint64,
 		Price:         nil,
 		Status:        "open",
 		Quantity:      dto.Quantity,
+		Unit:          productUnit,
 		AddedBy:       membr.UserID,
 		BoughtBy:      nil,
 		CreatedAt:     time.Now().UTC(),
+		Note:          dto.Note,
+		Priority:      cmp.Or(dto.Priority, product.PriorityNormal),
+		NeededBy:      dto.NeededBy,
+	}
+
+	merge := group.MergeDuplicates
+	if dto.Merge != nil {
+		merge = *dto.Merge
+	}
+
+	var merged bool
+	if merge {
+		product.ProductID, merged, err = s.productService.CreateOrMerge(ctx, product)
+	} else {
+		product.ProductID, err = s.productService.Create(ctx, product)
+	}
+
+	if err != nil {
+		return AddedProductDTO{}, err
+	}
+
+	if merged {
+		product, err = s.productService.GetById(ctx, product.ProductID)
+		if err != nil {
+			return AddedProductDTO{}, err
+		}
+	}
+
+	// the product is added already, so a failed estimate only leaves the estimate empty
+	estimatedPrice, err := s.priceService.Estimate(ctx, group.GroupID, product.ProductNameID, product.Quantity, product.Unit)
+	if err != nil {
+		estimatedPrice = nil
+	}
+
+	return AddedProductDTO{
+		ProductID:      product.ProductID,
+		EstimatedPrice: estimatedPrice,
+		Currency:       group.Currency,
+	}, nil
+}
+
+// resolveProductName finds the product name by id or by free text. An unknown
+// free text name becomes a private name of the group
+func (s *Service) resolveProductName(ctx context.Context, groupID uint64, dto CreateProductDTO) (product.ProductName, error) {
+	if dto.ProductNameID != 0 {
+		productName, err := s.productService.GetByProductNameId(ctx, dto.ProductNameID)
+		if err != nil {
+			return productName, err
+		}
+
+		if productName.GroupID != nil && *productName.GroupID != groupID {
+			return product.ProductName{}, domainErr.ErrProductNotFound
+		}
+
+		return productName, nil
+	}
+
+	name := strings.TrimSpace(dto.ProductName)
+	if name == "" {
+		return product.ProductName{}, domainErr.ErrProductNameEmpty
+	}
+
+	productName, err := s.productService.GetProductNameByName(ctx, groupID, name)
+	if err == nil {
+		return productName, nil
+	}
+
+	if !errors.Is(err, domainErr.ErrProductNotFound) {
+		return productName, err
+	}
+
+	if dto.CategoryID == 0 {
+		return productName, domainErr.ErrCategoryRequired
+	}
+
+	category, err := s.productService.GetCategoryById(ctx, dto.CategoryID)
+	if err != nil {
+		return productName, err
+	}
+
+	defaultUnit := dto.Unit
+	if defaultUnit == "" {
+		defaultUnit = unit.Pieces
+	}
+
+	// the name is kept even if adding the product fails, so it mustn't get a unit the product can't have
+	if !unit.IsValid(defaultUnit) {
+		return productName, domainErr.ErrInvalidUnit
+	}
+
+	productName = product.ProductName{
+		CategoryID:  category.CategoryID,
+		Name:        name,
+		GroupID:     &groupID,
+		DefaultUnit: defaultUnit,
+	}
+
+	productName.ProductNameID, err = s.productService.CreateProductName(ctx, productName)
+	if err != nil {
+		// another member has just added the same name
+		if errors.Is(err, domainErr.ErrProductNameAlreadyExists) {
+			return s.productService.GetProductNameByName(ctx, groupID, name)
+		}
+
+		return productName, err
+	}
+
+	return productName, nil
+}
+
+func (s *Service) AddCatalogProduct(ctx context.Context, dto CreateCatalogProductDTO) (uint64, error) {
+	group, err := s.repo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	category, err := s.productService.GetCategoryById(ctx, dto.CategoryID)
+	if err != nil {
+		return 0, err
+	}
+
+	defaultUnit := dto.DefaultUnit
+	if defaultUnit == "" {
+		defaultUnit = unit.Pieces
+	}
+
+	if !unit.IsValid(defaultUnit) {
+		return 0, domainErr.ErrInvalidUnit
+	}
+
+	name := strings.TrimSpace(dto.Name)
+
+	_, err = s.productService.GetProductNameByName(ctx, group.GroupID, name)
+	if err == nil {
+		return 0, domainErr.ErrProductNameAlreadyExists
+	}
+
+	if !errors.Is(err, domainErr.ErrProductNotFound) {
+		return 0, err
+	}
+
+	return s.productService.CreateProductName(ctx, product.ProductName{
+		CategoryID:  category.CategoryID,
+		Name:        name,
+		GroupID:     &group.GroupID,
+		DefaultUnit: defaultUnit,
+	})
+}
+
+func (s *Service) GetCatalogProducts(ctx context.Context, dto GroupUserDTO) ([]product.ProductName, error) {
+	group, err := s.repo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	return s.productService.GetGroupProductNames(ctx, group.GroupID)
+}
+
+// ParseProducts turns free text lines into quantity, unit and catalog suggestions.
+// With dto.Create the lines with a confident match are added to the group at once. The lines
+// are added one by one, so on an error it returns the lines handled before with the added products
+func (s *Service) ParseProducts(ctx context.Context, dto ParseProductsDTO) ([]ParsedProductDTO, error) {
+	group, err := s.repo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	parsed := make([]ParsedProductDTO, 0, len(dto.Lines))
+
+	for _, line := range dto.Lines {
+		result := lineparser.Parse(line)
+
+		matches, err := s.productService.Search(ctx, product.SearchDTO{
+			UserID:  dto.UserID,
+			GroupID: group.GroupID,
+			Query:   result.Name,
+			Locales: dto.Locales,
+			Limit:   suggestionsLimit,
+		})
+		if err != nil {
+			return parsed, err
+		}
+
+		suggestions := make([]ProductSuggestionDTO, 0, len(matches))
+		for _, match := range matches {
+			confidence := match.Score
+			if strings.EqualFold(match.Name, result.Name) {
+				confidence = 1
+			}
+
+			suggestions = append(suggestions, ProductSuggestionDTO{
+				ProductNameID: match.ProductNameID,
+				CategoryID:    match.CategoryID,
+				Name:          match.Name,
+				Confidence:    math.Round(confidence*100) / 100,
+			})
+		}
+
+		slices.SortStableFunc(suggestions, func(a, b ProductSuggestionDTO) int {
+			return cmp.Compare(b.Confidence, a.Confidence)
+		})
+
+		parsedProduct := ParsedProductDTO{
+			Line:        line,
+			Quantity:    result.Quantity,
+			Unit:        result.Unit,
+			Name:        result.Name,
+			Suggestions: suggestions,
+		}
+
+		if dto.Create && len(suggestions) > 0 && suggestions[0].Confidence >= autoCreateConfidence {
+			added, err := s.AddProduct(ctx, CreateProductDTO{
+				UserID:        dto.UserID,
+				GroupID:       group.GroupID,
+				ProductNameID: suggestions[0].ProductNameID,
+				Quantity:      result.Quantity,
+				Unit:          result.Unit,
+			})
+			if err != nil {
+				return parsed, err
+			}
+
+			parsedProduct.ProductID = &added.ProductID
+			parsedProduct.EstimatedPrice = added.EstimatedPrice
+		}
+
+		parsed = append(parsed, parsedProduct)
 	}
 
-	return s.productService.Create(ctx, product)
+	return parsed, nil
 }
 
 func (s *Service) RemoveProduct(ctx context.Context, dto RemoveProductDTO) error {
@@ -268,6 +585,12 @@ func (s *Service) RemoveProduct(ctx context.Context, dto RemoveProductDTO) error
 		return err
 	}
 
+	// the product id comes from the path, a product of another group is not found,
+	// so a member of one group can't remove the products of another
+	if product.GroupID != group.GroupID {
+		return domainErr.ErrProductNotFound
+	}
+
 	return s.productService.RemoveProduct(ctx, product.ProductID)
 }
 
@@ -291,15 +614,219 @@ func (s *Service) UpdateProduct(ctx context.Context, dto UpdateProductDTO) error
 		return err
 	}
 
+	// a product of another group is not found, so a member of one group can't change it
+	if product.GroupID != group.GroupID {
+		return domainErr.ErrProductNotFound
+	}
+
+	// closing an item claimed by someone else is likely a double purchase
+	if dto.Status == "closed" && product.Status == "open" && !dto.Force &&
+		product.AssignedTo != nil && *product.AssignedTo != membr.UserID {
+		return domainErr.ErrClaimedByAnotherMember
+	}
+
+	closing := dto.Status == "closed" && product.Status == "open"
+
+	if closing {
+		boughtAt := time.Now().UTC()
+		product.BoughtAt = &boughtAt
+	}
+
+	// a reopened product is no longer part of the trip it was bought on, so it isn't
+	// taken off the total of the trip
+	if dto.Status == "open" {
+		product.BoughtAt = nil
+		product.TripID = nil
+	}
+
 	product.Status = dto.Status
 	product.Quantity = dto.Quantity
+
+	if dto.Unit != "" {
+		if !unit.IsValid(dto.Unit) {
+			return domainErr.ErrInvalidUnit
+		}
+
+		product.Unit = dto.Unit
+	}
 	product.Price = dto.Price
 	product.BoughtBy = &membr.UserID
 
-	return s.productService.Update(ctx, product)
+	if dto.Currency != "" {
+		if err = s.checkCurrency(ctx, dto.Currency); err != nil {
+			return err
+		}
+
+		product.Currency = &dto.Currency
+	}
+
+	if dto.Note != nil {
+		product.Note = *dto.Note
+	}
+
+	if dto.Priority != "" {
+		product.Priority = dto.Priority
+	}
+
+	if dto.NeededBy != nil {
+		product.NeededBy = dto.NeededBy
+	}
+
+	if dto.ClearNeededBy {
+		product.NeededBy = nil
+	}
+
+	// the stock of a bought product is added in the same transaction as closing it
+	if closing && dto.ToPantry {
+		err = s.productService.UpdateToPantry(ctx, product, dto.ExpiresAt)
+	} else {
+		err = s.productService.Update(ctx, product)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the product is saved already, so a failed check is told apart from a failed update
+	if closing && product.Price != nil {
+		if err = s.budgetService.CheckThresholds(ctx, group.GroupID); err != nil {
+			return fmt.Errorf("%w: %w", domainErr.ErrThresholdsNotChecked, err)
+		}
+	}
+
+	return nil
+}
+
+// ClaimProduct assigns the open product to the member who is going to buy it. The owner
+// can assign it to any member and take it over from another one
+func (s *Service) ClaimProduct(ctx context.Context, dto ClaimProductDTO) error {
+	group, err := s.repo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrMemberNotFound
+		}
+	}
+
+	assignee := membr
+	if dto.MemberID != 0 && dto.MemberID != membr.MemberID {
+		if membr.Role != "owner" {
+			return domainErr.ErrAreNotOwner
+		}
+
+		assignee, err = s.memberRepo.GetByMemberAndGroupId(ctx, dto.MemberID, group.GroupID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domainErr.ErrMemberNotFound
+			}
+		}
+	}
+
+	product, err := s.productService.GetById(ctx, dto.ProductID)
+	if err != nil {
+		return err
+	}
+
+	if product.GroupID != group.GroupID {
+		return domainErr.ErrProductNotFound
+	}
+
+	if product.Status != "open" {
+		return domainErr.ErrProductClosed
+	}
+
+	if product.AssignedTo != nil && *product.AssignedTo != assignee.UserID && membr.Role != "owner" {
+		return domainErr.ErrClaimedByAnotherMember
+	}
+
+	claimedAt := time.Now().UTC()
+
+	return s.productService.Assign(ctx, product.ProductID, &assignee.UserID, &claimedAt)
 }
 
-func (s *Service) GetGroupProducts(ctx context.Context, dto GroupUserDTO) ([]product.ProductDTO, error) {
+// ReleaseProduct removes the claim, only the assignee and the owner can do it
+func (s *Service) ReleaseProduct(ctx context.Context, dto ClaimProductDTO) error {
+	group, err := s.repo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrMemberNotFound
+		}
+	}
+
+	product, err := s.productService.GetById(ctx, dto.ProductID)
+	if err != nil {
+		return err
+	}
+
+	if product.GroupID != group.GroupID {
+		return domainErr.ErrProductNotFound
+	}
+
+	if product.AssignedTo == nil {
+		return nil
+	}
+
+	if *product.AssignedTo != membr.UserID && membr.Role != "owner" {
+		return domainErr.ErrClaimedByAnotherMember
+	}
+
+	return s.productService.Assign(ctx, product.ProductID, nil, nil)
+}
+
+// MoveProduct puts the product next to another product of the group, the listing
+// without a sort follows this order
+func (s *Service) MoveProduct(ctx context.Context, dto MoveProductDTO) error {
+	group, err := s.repo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrMemberNotFound
+		}
+	}
+
+	anchorID, after := dto.Before, false
+	if dto.After != 0 {
+		anchorID, after = dto.After, true
+	}
+
+	if anchorID == dto.ProductID {
+		return domainErr.ErrCannotMoveNextToItself
+	}
+
+	for _, productID := range []uint64{dto.ProductID, anchorID} {
+		product, err := s.productService.GetById(ctx, productID)
+		if err != nil {
+			return err
+		}
+
+		if product.GroupID != group.GroupID {
+			return domainErr.ErrProductNotFound
+		}
+	}
+
+	return s.productService.Move(ctx, dto.ProductID, anchorID, after)
+}
+
+func (s *Service) GetProductRequests(ctx context.Context, dto ProductRequestsDTO) ([]product.ProductRequestDTO, error) {
 	group, err := s.repo.GetById(ctx, dto.GroupID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -314,12 +841,119 @@ func (s *Service) GetGroupProducts(ctx context.Context, dto GroupUserDTO) ([]pro
 		}
 	}
 
-	products, err := s.productService.GetGroupProducts(ctx, group.GroupID)
+	product, err := s.productService.GetById(ctx, dto.ProductID)
 	if err != nil {
 		return nil, err
 	}
 
-	return products, nil
+	if product.GroupID != group.GroupID {
+		return nil, domainErr.ErrProductNotFound
+	}
+
+	return s.productService.GetProductRequests(ctx, product.ProductID)
+}
+
+func (s *Service) DedupeProducts(ctx context.Context, dto GroupUserDTO) (int, error) {
+	group, err := s.repo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	return s.productService.Dedupe(ctx, group.GroupID)
+}
+
+func (s *Service) UpdateSettings(ctx context.Context, dto UpdateSettingsDTO) error {
+	group, err := s.repo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrMemberNotFound
+		}
+	}
+
+	if membr.Role != "owner" {
+		return domainErr.ErrAreNotOwner
+	}
+
+	if dto.MergeDuplicates != nil {
+		group.MergeDuplicates = *dto.MergeDuplicates
+	}
+
+	if dto.SplitRule != "" {
+		group.SplitRule = dto.SplitRule
+	}
+
+	if dto.Currency != "" {
+		if err = s.checkCurrency(ctx, dto.Currency); err != nil {
+			return err
+		}
+
+		group.Currency = dto.Currency
+	}
+
+	return s.repo.UpdateSettings(ctx, group)
+}
+
+// checkCurrency makes sure the currency has an exchange rate
+func (s *Service) checkCurrency(ctx context.Context, code string) error {
+	_, err := s.currencyRepo.GetRate(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrUnknownCurrency
+		}
+
+		return fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetGroupProducts(ctx context.Context, dto GroupProductsDTO) (product.ProductPageDTO, error) {
+	group, err := s.repo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return product.ProductPageDTO{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return product.ProductPageDTO{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	// in a store the open products are walked through section by section
+	if dto.Filter.StoreID != 0 {
+		storeGroupID, err := s.storeRepo.GetStoreGroupId(ctx, dto.Filter.StoreID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return product.ProductPageDTO{}, fmt.Errorf("failed to get store: %w", err)
+		}
+
+		if err != nil || storeGroupID != group.GroupID {
+			return product.ProductPageDTO{}, domainErr.ErrStoreNotFound
+		}
+
+		dto.Filter.Status = cmp.Or(dto.Filter.Status, "open")
+		dto.Filter.Sort = cmp.Or(dto.Filter.Sort, "store")
+	}
+
+	return s.productService.GetGroupProducts(ctx, group.GroupID, dto.Locales, dto.Filter)
 }
 
 func (s *Service) GenCode(size int64) (string, error) {
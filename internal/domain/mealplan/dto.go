@@ -0,0 +1,62 @@
+package mealplan
+
+import (
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"time"
+)
+
+type CreateEntryDTO struct {
+	GroupID  uint64
+	UserID   uint64
+	Date     time.Time
+	Slot     string
+	RecipeID uint64
+	Servings int
+	Text     string
+}
+
+// UpdateEntryDTO changes only the given fields, ClearRecipe turns the entry into free text
+type UpdateEntryDTO struct {
+	GroupID     uint64
+	UserID      uint64
+	EntryID     uint64
+	Date        *time.Time
+	Slot        string
+	RecipeID    uint64
+	Servings    int
+	Text        *string
+	ClearRecipe bool
+}
+
+type EntryUserDTO struct {
+	GroupID uint64
+	UserID  uint64
+	EntryID uint64
+}
+
+// PeriodDTO selects the days from From to To, both included
+type PeriodDTO struct {
+	GroupID uint64
+	UserID  uint64
+	From    time.Time
+	To      time.Time
+}
+
+type EntryDTO struct {
+	EntryID    uint64    `json:"entry_id" db:"entry_id"`
+	Date       time.Time `json:"date" db:"plan_date"`
+	Slot       string    `json:"slot" db:"slot"`
+	RecipeID   *uint64   `json:"recipe_id" db:"recipe_id"`
+	RecipeName *string   `json:"recipe_name" db:"recipe_name"`
+	Servings   *int      `json:"servings" db:"servings"`
+	Text       string    `json:"text" db:"text"`
+	CreatedBy  uint64    `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// GeneratedListDTO lists the products added or merged for the plan, Covered are the product names
+// the list and the pantry of the group have enough of
+type GeneratedListDTO struct {
+	Products []group.AddedProductDTO
+	Covered  []uint64
+}
@@ -0,0 +1,31 @@
+package mealplan
+
+import "time"
+
+const (
+	SlotBreakfast = "breakfast"
+	SlotLunch     = "lunch"
+	SlotDinner    = "dinner"
+	SlotSnack     = "snack"
+)
+
+// Entry of the meal plan of the group is a recipe or free text, Servings is the servings
+// of the recipe when nil
+type Entry struct {
+	EntryID   uint64
+	GroupID   uint64
+	Date      time.Time
+	Slot      string
+	RecipeID  *uint64
+	Servings  *int
+	Text      string
+	CreatedBy uint64
+	CreatedAt time.Time
+}
+
+// PlannedIngredient is an ingredient of a planned recipe scaled to the servings of the entry
+type PlannedIngredient struct {
+	ProductNameID uint64
+	Quantity      float64
+	Unit          string
+}
@@ -0,0 +1,404 @@
+package mealplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/pantry"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"github.com/tclutin/shoppinglist-api/internal/domain/recipe"
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+	"math"
+	"time"
+)
+
+// maxPeriodDays is the longest period the plan is read or the list is generated for
+const maxPeriodDays = 62
+
+type Repository interface {
+	Create(ctx context.Context, entry Entry) (uint64, error)
+	GetById(ctx context.Context, entryID uint64) (Entry, error)
+	Update(ctx context.Context, entry Entry) error
+	Delete(ctx context.Context, entryID uint64) error
+	GetEntries(ctx context.Context, groupID uint64, from time.Time, to time.Time) ([]EntryDTO, error)
+	GetPlannedIngredients(ctx context.Context, groupID uint64, from time.Time, to time.Time) ([]PlannedIngredient, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type RecipeRepository interface {
+	GetById(ctx context.Context, recipeID uint64) (recipe.Recipe, error)
+}
+
+type ProductRepository interface {
+	GetOpenProductsByGroupId(ctx context.Context, groupID uint64) ([]product.Product, error)
+}
+
+type PantryRepository interface {
+	GetByProductNameId(ctx context.Context, groupID uint64, productNameID uint64) ([]pantry.Item, error)
+}
+
+type GroupService interface {
+	AddProduct(ctx context.Context, dto group.CreateProductDTO) (group.AddedProductDTO, error)
+}
+
+type Service struct {
+	repo         Repository
+	groupRepo    GroupRepository
+	memberRepo   MemberRepository
+	recipeRepo   RecipeRepository
+	productRepo  ProductRepository
+	pantryRepo   PantryRepository
+	groupService GroupService
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, recipeRepo RecipeRepository, productRepo ProductRepository, pantryRepo PantryRepository, groupService GroupService) *Service {
+	return &Service{
+		repo:         repo,
+		groupRepo:    groupRepo,
+		memberRepo:   memberRepo,
+		recipeRepo:   recipeRepo,
+		productRepo:  productRepo,
+		pantryRepo:   pantryRepo,
+		groupService: groupService,
+	}
+}
+
+func (s *Service) CreateEntry(ctx context.Context, dto CreateEntryDTO) (uint64, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	entry := Entry{
+		GroupID:   group.GroupID,
+		Date:      dto.Date,
+		Slot:      dto.Slot,
+		Text:      dto.Text,
+		CreatedBy: membr.UserID,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if dto.RecipeID != 0 {
+		if err = s.checkRecipe(ctx, group.GroupID, dto.RecipeID); err != nil {
+			return 0, err
+		}
+
+		entry.RecipeID = &dto.RecipeID
+	}
+
+	if dto.Servings != 0 {
+		entry.Servings = &dto.Servings
+	}
+
+	return s.repo.Create(ctx, entry)
+}
+
+// GetPlan returns the entries of the period by day and slot
+func (s *Service) GetPlan(ctx context.Context, dto PeriodDTO) ([]EntryDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	if err = checkPeriod(dto.From, dto.To); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.GetEntries(ctx, group.GroupID, dto.From, dto.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meal plan: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (s *Service) UpdateEntry(ctx context.Context, dto UpdateEntryDTO) error {
+	entry, err := s.getEntry(ctx, EntryUserDTO{
+		GroupID: dto.GroupID,
+		UserID:  dto.UserID,
+		EntryID: dto.EntryID,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if dto.Date != nil {
+		entry.Date = *dto.Date
+	}
+
+	if dto.Slot != "" {
+		entry.Slot = dto.Slot
+	}
+
+	if dto.Text != nil {
+		entry.Text = *dto.Text
+	}
+
+	if dto.RecipeID != 0 {
+		if err = s.checkRecipe(ctx, entry.GroupID, dto.RecipeID); err != nil {
+			return err
+		}
+
+		entry.RecipeID = &dto.RecipeID
+	}
+
+	if dto.ClearRecipe {
+		entry.RecipeID = nil
+		entry.Servings = nil
+	}
+
+	if dto.Servings != 0 && entry.RecipeID != nil {
+		entry.Servings = &dto.Servings
+	}
+
+	// an entry without a recipe is the text
+	if entry.RecipeID == nil && entry.Text == "" {
+		return domainErr.ErrMealPlanEntryEmpty
+	}
+
+	return s.repo.Update(ctx, entry)
+}
+
+func (s *Service) DeleteEntry(ctx context.Context, dto EntryUserDTO) error {
+	entry, err := s.getEntry(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, entry.EntryID)
+}
+
+// GenerateList adds what the recipes planned for the period need to the list of the group. The ingredients
+// are summed up in compatible units, what the open products and the pantry have is subtracted and only
+// the rest is added, merging with the open products, so generating the list again adds nothing new.
+// The products are added one by one, so an error can leave the list generated partly
+func (s *Service) GenerateList(ctx context.Context, dto PeriodDTO) (GeneratedListDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return GeneratedListDTO{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return GeneratedListDTO{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	if err = checkPeriod(dto.From, dto.To); err != nil {
+		return GeneratedListDTO{}, err
+	}
+
+	ingredients, err := s.repo.GetPlannedIngredients(ctx, group.GroupID, dto.From, dto.To)
+	if err != nil {
+		return GeneratedListDTO{}, fmt.Errorf("failed to get planned ingredients: %w", err)
+	}
+
+	needs := sumUp(ingredients)
+
+	products, err := s.productRepo.GetOpenProductsByGroupId(ctx, group.GroupID)
+	if err != nil {
+		return GeneratedListDTO{}, fmt.Errorf("failed to get open products: %w", err)
+	}
+
+	for _, product := range products {
+		subtract(needs, product.ProductNameID, product.Quantity, product.Unit)
+	}
+
+	// a product name planned in incompatible units has several needs, but its pantry counts once
+	inPantry := make(map[uint64]bool, len(needs))
+
+	for _, need := range needs {
+		if inPantry[need.ProductNameID] {
+			continue
+		}
+		inPantry[need.ProductNameID] = true
+
+		items, err := s.pantryRepo.GetByProductNameId(ctx, group.GroupID, need.ProductNameID)
+		if err != nil {
+			return GeneratedListDTO{}, fmt.Errorf("failed to get pantry items: %w", err)
+		}
+
+		for _, item := range items {
+			subtract(needs, item.ProductNameID, item.Quantity, item.Unit)
+		}
+	}
+
+	return s.addNeeds(ctx, membr, needs)
+}
+
+// addNeeds adds the rest of the needs to the list on behalf of the member, merging with the open products
+func (s *Service) addNeeds(ctx context.Context, membr member.Member, needs []*PlannedIngredient) (GeneratedListDTO, error) {
+	generated := GeneratedListDTO{
+		Products: make([]group.AddedProductDTO, 0, len(needs)),
+		Covered:  make([]uint64, 0),
+	}
+
+	merge := true
+
+	for _, need := range needs {
+		// quantities are kept with three decimals
+		quantity := math.Round(need.Quantity*1000) / 1000
+		if quantity <= 0 {
+			generated.Covered = append(generated.Covered, need.ProductNameID)
+			continue
+		}
+
+		added, err := s.groupService.AddProduct(ctx, group.CreateProductDTO{
+			UserID:        membr.UserID,
+			GroupID:       membr.GroupID,
+			ProductNameID: need.ProductNameID,
+			Quantity:      quantity,
+			Unit:          need.Unit,
+			Merge:         &merge,
+		})
+
+		if err != nil {
+			return generated, err
+		}
+
+		generated.Products = append(generated.Products, added)
+	}
+
+	return generated, nil
+}
+
+// sumUp sums the quantities of each product name up in the unit it is planned in first,
+// quantities in incompatible units are kept apart
+func sumUp(ingredients []PlannedIngredient) []*PlannedIngredient {
+	needs := make([]*PlannedIngredient, 0, len(ingredients))
+
+	for _, ingredient := range ingredients {
+		summed := false
+		for _, need := range needs {
+			if need.ProductNameID != ingredient.ProductNameID {
+				continue
+			}
+
+			if quantity, ok := unit.Convert(ingredient.Quantity, ingredient.Unit, need.Unit); ok {
+				need.Quantity += quantity
+				summed = true
+				break
+			}
+		}
+
+		if !summed {
+			need := ingredient
+			needs = append(needs, &need)
+		}
+	}
+
+	return needs
+}
+
+// subtract takes the quantity off the need of the product name in a compatible unit
+func subtract(needs []*PlannedIngredient, productNameID uint64, quantity float64, quantityUnit string) {
+	for _, need := range needs {
+		if need.ProductNameID != productNameID || need.Quantity <= 0 {
+			continue
+		}
+
+		converted, ok := unit.Convert(quantity, quantityUnit, need.Unit)
+		if !ok {
+			continue
+		}
+
+		used := min(need.Quantity, converted)
+		need.Quantity -= used
+
+		quantity, _ = unit.Convert(converted-used, need.Unit, quantityUnit)
+		if quantity <= 0 {
+			return
+		}
+	}
+}
+
+func checkPeriod(from time.Time, to time.Time) error {
+	if to.Before(from) || to.Sub(from) > maxPeriodDays*24*time.Hour {
+		return domainErr.ErrInvalidPeriod
+	}
+
+	return nil
+}
+
+// checkRecipe checks that the recipe belongs to the group
+func (s *Service) checkRecipe(ctx context.Context, groupID uint64, recipeID uint64) error {
+	rcp, err := s.recipeRepo.GetById(ctx, recipeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrRecipeNotFound
+		}
+
+		return fmt.Errorf("failed to get recipe: %w", err)
+	}
+
+	if rcp.GroupID != groupID {
+		return domainErr.ErrRecipeNotFound
+	}
+
+	return nil
+}
+
+// getEntry returns the entry of the meal plan of the group, if the user is a member of the group
+func (s *Service) getEntry(ctx context.Context, dto EntryUserDTO) (Entry, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Entry{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Entry{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	entry, err := s.repo.GetById(ctx, dto.EntryID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Entry{}, domainErr.ErrMealPlanEntryNotFound
+		}
+
+		return Entry{}, fmt.Errorf("failed to get meal plan entry: %w", err)
+	}
+
+	if entry.GroupID != group.GroupID {
+		return Entry{}, domainErr.ErrMealPlanEntryNotFound
+	}
+
+	return entry, nil
+}
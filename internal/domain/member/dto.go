@@ -1,8 +1,9 @@
 package member
 
 type MemberDTO struct {
-	MemberID uint64 `json:"member_id"  db:"member_id"`
-	Username string `json:"username" db:"username"`
-	Gender   string `json:"gender" db:"gender"`
-	Role     string `json:"role" db:"role"`
+	MemberID uint64  `json:"member_id"  db:"member_id"`
+	Username string  `json:"username" db:"username"`
+	Gender   string  `json:"gender" db:"gender"`
+	Role     string  `json:"role" db:"role"`
+	Share    float64 `json:"share" db:"share"`
 }
@@ -8,4 +8,5 @@ type Member struct {
 	GroupID  uint64
 	Role     string
 	JoinedAt time.Time
+	Share    float64
 }
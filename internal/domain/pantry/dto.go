@@ -0,0 +1,71 @@
+package pantry
+
+import (
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"time"
+)
+
+type AddItemDTO struct {
+	GroupID       uint64
+	UserID        uint64
+	ProductNameID uint64
+	Quantity      float64
+	Unit          string
+	ExpiresAt     *time.Time
+}
+
+type GroupPantryDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+	Filter  Filter
+}
+
+type ItemUserDTO struct {
+	GroupID      uint64
+	UserID       uint64
+	PantryItemID uint64
+}
+
+// UpdateItemDTO changes only the given fields, the Clear flags remove the expiry date and the threshold
+type UpdateItemDTO struct {
+	GroupID                uint64
+	UserID                 uint64
+	PantryItemID           uint64
+	Quantity               *float64
+	ExpiresAt              *time.Time
+	ClearExpiresAt         bool
+	LowStockThreshold      *float64
+	RestockQuantity        *float64
+	ClearLowStockThreshold bool
+}
+
+// ConsumeDTO takes the quantity out of the stock, Unit is the unit of the item when empty
+type ConsumeDTO struct {
+	GroupID      uint64
+	UserID       uint64
+	PantryItemID uint64
+	Quantity     float64
+	Unit         string
+}
+
+// ConsumedDTO is the stock left, Restocked is the product put on the list when the stock ran low
+type ConsumedDTO struct {
+	Quantity  float64
+	Unit      string
+	Restocked *group.AddedProductDTO
+}
+
+type ItemDTO struct {
+	PantryItemID      uint64     `json:"pantry_item_id" db:"pantry_item_id"`
+	ProductNameID     uint64     `json:"product_name_id" db:"product_name_id"`
+	ProductName       string     `json:"product_name" db:"product_name"`
+	CategoryID        uint64     `json:"category_id" db:"category_id"`
+	Quantity          float64    `json:"quantity" db:"quantity"`
+	Unit              string     `json:"unit" db:"unit"`
+	ExpiresAt         *time.Time `json:"expires_at" db:"expires_at"`
+	LowStockThreshold *float64   `json:"low_stock_threshold" db:"low_stock_threshold"`
+	RestockQuantity   *float64   `json:"restock_quantity" db:"restock_quantity"`
+	LowStock          bool       `json:"low_stock" db:"low_stock"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package pantry
+
+import "time"
+
+// Item is the stock of a product name the group has at home. ExpiresAt is the earliest
+// expiry date of the stock, RestockQuantity is put on the list when the stock drops
+// to LowStockThreshold, both are set or both are nil
+type Item struct {
+	PantryItemID      uint64
+	GroupID           uint64
+	ProductNameID     uint64
+	Quantity          float64
+	Unit              string
+	ExpiresAt         *time.Time
+	LowStockThreshold *float64
+	RestockQuantity   *float64
+	UpdatedAt         time.Time
+}
+
+// Filter narrows the pantry, zero values don't filter
+type Filter struct {
+	// ExpiringWithinDays keeps the items that expire in that many days, including the expired ones
+	ExpiringWithinDays int
+	LowStock           bool
+}
@@ -0,0 +1,307 @@
+package pantry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+	"time"
+)
+
+type Repository interface {
+	AddStock(ctx context.Context, groupID uint64, productNameID uint64, quantity float64, unit string, expiresAt *time.Time) (uint64, error)
+	GetById(ctx context.Context, pantryItemID uint64) (Item, error)
+	Update(ctx context.Context, item Item) error
+	Delete(ctx context.Context, pantryItemID uint64) error
+	Consume(ctx context.Context, pantryItemID uint64, quantity float64) (float64, float64, error)
+	GetItems(ctx context.Context, groupID uint64, locales []string, filter Filter) ([]ItemDTO, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type ProductRepository interface {
+	GetByProductNameId(ctx context.Context, productNameID uint64) (product.ProductName, error)
+	GetOpenProductsByGroupId(ctx context.Context, groupID uint64) ([]product.Product, error)
+}
+
+type GroupService interface {
+	AddProduct(ctx context.Context, dto group.CreateProductDTO) (group.AddedProductDTO, error)
+}
+
+type Service struct {
+	repo         Repository
+	groupRepo    GroupRepository
+	memberRepo   MemberRepository
+	productRepo  ProductRepository
+	groupService GroupService
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, productRepo ProductRepository, groupService GroupService) *Service {
+	return &Service{
+		repo:         repo,
+		groupRepo:    groupRepo,
+		memberRepo:   memberRepo,
+		productRepo:  productRepo,
+		groupService: groupService,
+	}
+}
+
+// AddItem adds the quantity to the stock of the product name, a new item is created
+// when the group has none in a compatible unit
+func (s *Service) AddItem(ctx context.Context, dto AddItemDTO) (uint64, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	productName, err := s.productRepo.GetByProductNameId(ctx, dto.ProductNameID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrProductNotFound
+		}
+
+		return 0, fmt.Errorf("failed to get product name: %w", err)
+	}
+
+	if productName.GroupID != nil && *productName.GroupID != group.GroupID {
+		return 0, domainErr.ErrProductNotFound
+	}
+
+	itemUnit := dto.Unit
+	if itemUnit == "" {
+		itemUnit = productName.DefaultUnit
+	}
+
+	if !unit.IsValid(itemUnit) {
+		return 0, domainErr.ErrInvalidUnit
+	}
+
+	return s.repo.AddStock(ctx, group.GroupID, productName.ProductNameID, dto.Quantity, itemUnit, dto.ExpiresAt)
+}
+
+// GetPantry returns the stock of the group, the items expiring first come first
+func (s *Service) GetPantry(ctx context.Context, dto GroupPantryDTO) ([]ItemDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	items, err := s.repo.GetItems(ctx, group.GroupID, dto.Locales, dto.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pantry items: %w", err)
+	}
+
+	return items, nil
+}
+
+// UpdateItem changes the item, like Consume it puts the restock quantity on the list
+// when the change makes the stock low, with a lower quantity or a higher threshold
+func (s *Service) UpdateItem(ctx context.Context, dto UpdateItemDTO) (*group.AddedProductDTO, error) {
+	item, err := s.getItem(ctx, ItemUserDTO{
+		GroupID:      dto.GroupID,
+		UserID:       dto.UserID,
+		PantryItemID: dto.PantryItemID,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	wasLow := isLow(item)
+
+	if dto.Quantity != nil {
+		item.Quantity = *dto.Quantity
+	}
+
+	if dto.ExpiresAt != nil {
+		item.ExpiresAt = dto.ExpiresAt
+	}
+
+	if dto.ClearExpiresAt {
+		item.ExpiresAt = nil
+	}
+
+	if dto.LowStockThreshold != nil {
+		item.LowStockThreshold = dto.LowStockThreshold
+	}
+
+	if dto.RestockQuantity != nil {
+		item.RestockQuantity = dto.RestockQuantity
+	}
+
+	if dto.ClearLowStockThreshold {
+		item.LowStockThreshold = nil
+		item.RestockQuantity = nil
+	}
+
+	// the threshold is useless without the quantity to buy
+	if (item.LowStockThreshold == nil) != (item.RestockQuantity == nil) {
+		return nil, domainErr.ErrRestockQuantityRequired
+	}
+
+	item.UpdatedAt = time.Now().UTC()
+
+	if err = s.repo.Update(ctx, item); err != nil {
+		return nil, fmt.Errorf("failed to update pantry item: %w", err)
+	}
+
+	if wasLow || !isLow(item) {
+		return nil, nil
+	}
+
+	return s.restock(ctx, item, dto.UserID)
+}
+
+func (s *Service) DeleteItem(ctx context.Context, dto ItemUserDTO) error {
+	item, err := s.getItem(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, item.PantryItemID)
+}
+
+// Consume takes the quantity out of the stock, never below zero. When the stock drops to the
+// low stock threshold the restock quantity is put on the list, unless the product is on it already
+func (s *Service) Consume(ctx context.Context, dto ConsumeDTO) (ConsumedDTO, error) {
+	item, err := s.getItem(ctx, ItemUserDTO{
+		GroupID:      dto.GroupID,
+		UserID:       dto.UserID,
+		PantryItemID: dto.PantryItemID,
+	})
+
+	if err != nil {
+		return ConsumedDTO{}, err
+	}
+
+	quantity := dto.Quantity
+	if dto.Unit != "" {
+		var ok bool
+		quantity, ok = unit.Convert(dto.Quantity, dto.Unit, item.Unit)
+		if !ok {
+			return ConsumedDTO{}, domainErr.ErrInvalidUnit
+		}
+	}
+
+	before, after, err := s.repo.Consume(ctx, item.PantryItemID, quantity)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ConsumedDTO{}, domainErr.ErrPantryItemNotFound
+		}
+
+		return ConsumedDTO{}, fmt.Errorf("failed to consume pantry item: %w", err)
+	}
+
+	consumed := ConsumedDTO{
+		Quantity: after,
+		Unit:     item.Unit,
+	}
+
+	threshold := item.LowStockThreshold
+	if threshold == nil || before <= *threshold || after > *threshold {
+		return consumed, nil
+	}
+
+	restocked, err := s.restock(ctx, item, dto.UserID)
+	if err != nil {
+		return consumed, err
+	}
+
+	consumed.Restocked = restocked
+
+	return consumed, nil
+}
+
+// restock puts the restock quantity of the item on the list, nil when an open product has the same name
+func (s *Service) restock(ctx context.Context, item Item, userID uint64) (*group.AddedProductDTO, error) {
+	products, err := s.productRepo.GetOpenProductsByGroupId(ctx, item.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open products: %w", err)
+	}
+
+	for _, product := range products {
+		if product.ProductNameID == item.ProductNameID {
+			return nil, nil
+		}
+	}
+
+	added, err := s.groupService.AddProduct(ctx, group.CreateProductDTO{
+		UserID:        userID,
+		GroupID:       item.GroupID,
+		ProductNameID: item.ProductNameID,
+		Quantity:      *item.RestockQuantity,
+		Unit:          item.Unit,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// isLow tells whether the stock is at or below the low stock threshold of the item
+func isLow(item Item) bool {
+	return item.LowStockThreshold != nil && item.Quantity <= *item.LowStockThreshold
+}
+
+// getItem returns the pantry item, if it belongs to the group and the user is a member of the group
+func (s *Service) getItem(ctx context.Context, dto ItemUserDTO) (Item, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Item{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Item{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	item, err := s.repo.GetById(ctx, dto.PantryItemID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Item{}, domainErr.ErrPantryItemNotFound
+		}
+
+		return Item{}, fmt.Errorf("failed to get pantry item: %w", err)
+	}
+
+	if item.GroupID != group.GroupID {
+		return Item{}, domainErr.ErrPantryItemNotFound
+	}
+
+	return item, nil
+}
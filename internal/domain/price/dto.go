@@ -0,0 +1,42 @@
+package price
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type ProductPricesDTO struct {
+	GroupID       uint64
+	UserID        uint64
+	ProductNameID uint64
+	// Limit is how many latest points of every unit are returned
+	Limit int
+}
+
+type PricePointDTO struct {
+	Quantity   float64         `json:"quantity"`
+	Price      decimal.Decimal `json:"price"`
+	UnitPrice  decimal.Decimal `json:"unit_price"`
+	RecordedAt time.Time       `json:"recorded_at"`
+}
+
+// UnitPricesDTO is the price summary of one unit, the prices are per unit
+type UnitPricesDTO struct {
+	Unit  string          `json:"unit"`
+	Count int             `json:"count"`
+	Min   decimal.Decimal `json:"min"`
+	Avg   decimal.Decimal `json:"avg"`
+	Max   decimal.Decimal `json:"max"`
+	Last  decimal.Decimal `json:"last"`
+	Trend string          `json:"trend"`
+	// Change is the difference of the last price from the average of the previous ones, in percent
+	Change  float64         `json:"change"`
+	History []PricePointDTO `json:"history"`
+}
+
+// PricesDTO has the prices in the currency of the group
+type PricesDTO struct {
+	ProductNameID uint64          `json:"product_name_id"`
+	Currency      string          `json:"currency"`
+	Units         []UnitPricesDTO `json:"units"`
+}
@@ -0,0 +1,23 @@
+package price
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	TrendUp      = "up"
+	TrendDown    = "down"
+	TrendStable  = "stable"
+	TrendUnknown = "unknown"
+)
+
+// PricePoint is the price paid for a bought product, it outlives the product. Price is
+// converted to the currency of the group
+type PricePoint struct {
+	ProductID  *uint64         `db:"product_id"`
+	Quantity   float64         `db:"quantity"`
+	Unit       string          `db:"unit"`
+	Price      decimal.Decimal `db:"price"`
+	RecordedAt time.Time       `db:"recorded_at"`
+}
@@ -0,0 +1,203 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+)
+
+const (
+	// trendWindow is how many prices before the last one it is compared with
+	trendWindow = 5
+
+	// trendThreshold is the change in percent below which the price is stable
+	trendThreshold = 5
+
+	// estimateWindow is how many latest prices the estimate is averaged over
+	estimateWindow = 3
+)
+
+type Repository interface {
+	GetPrices(ctx context.Context, groupID uint64, productNameID uint64) ([]PricePoint, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type ProductRepository interface {
+	GetByProductNameId(ctx context.Context, productNameID uint64) (product.ProductName, error)
+}
+
+type Service struct {
+	repo        Repository
+	groupRepo   GroupRepository
+	memberRepo  MemberRepository
+	productRepo ProductRepository
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, productRepo ProductRepository) *Service {
+	return &Service{
+		repo:        repo,
+		groupRepo:   groupRepo,
+		memberRepo:  memberRepo,
+		productRepo: productRepo,
+	}
+}
+
+// GetPrices returns the price summary of the product name in the group for every unit it was bought in,
+// the most recently bought unit first
+func (s *Service) GetPrices(ctx context.Context, dto ProductPricesDTO) (PricesDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PricesDTO{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PricesDTO{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	productName, err := s.productRepo.GetByProductNameId(ctx, dto.ProductNameID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PricesDTO{}, domainErr.ErrProductNotFound
+		}
+
+		return PricesDTO{}, fmt.Errorf("failed to get product name: %w", err)
+	}
+
+	if productName.GroupID != nil && *productName.GroupID != group.GroupID {
+		return PricesDTO{}, domainErr.ErrProductNotFound
+	}
+
+	points, err := s.repo.GetPrices(ctx, group.GroupID, productName.ProductNameID)
+	if err != nil {
+		return PricesDTO{}, fmt.Errorf("failed to get prices: %w", err)
+	}
+
+	var units []string
+	byUnit := make(map[string][]PricePoint)
+	for _, point := range points {
+		if _, ok := byUnit[point.Unit]; !ok {
+			units = append(units, point.Unit)
+		}
+
+		byUnit[point.Unit] = append(byUnit[point.Unit], point)
+	}
+
+	prices := PricesDTO{
+		ProductNameID: productName.ProductNameID,
+		Currency:      group.Currency,
+		Units:         make([]UnitPricesDTO, 0, len(units)),
+	}
+
+	for _, productUnit := range units {
+		prices.Units = append(prices.Units, summarize(productUnit, byUnit[productUnit], dto.Limit))
+	}
+
+	return prices, nil
+}
+
+// Estimate returns the expected price of the quantity of the product name in the currency of the group
+// from the latest prices the group paid in compatible units, nil when the group has never bought it
+func (s *Service) Estimate(ctx context.Context, groupID uint64, productNameID uint64, quantity float64, productUnit string) (*decimal.Decimal, error) {
+	points, err := s.repo.GetPrices(ctx, groupID, productNameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prices: %w", err)
+	}
+
+	sum := decimal.Zero
+	var count int64
+	for _, point := range points {
+		pointQuantity, ok := unit.Convert(point.Quantity, point.Unit, productUnit)
+		if !ok || pointQuantity == 0 {
+			continue
+		}
+
+		sum = sum.Add(point.Price.Div(decimal.NewFromFloat(pointQuantity)))
+		count++
+
+		if count == estimateWindow {
+			break
+		}
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	estimate := sum.Div(decimal.NewFromInt(count)).Mul(decimal.NewFromFloat(quantity)).Round(2)
+
+	return &estimate, nil
+}
+
+// summarize computes the summary of the prices of one unit, the points are the newest first
+func summarize(productUnit string, points []PricePoint, limit int) UnitPricesDTO {
+	summary := UnitPricesDTO{
+		Unit:    productUnit,
+		Count:   len(points),
+		Trend:   TrendUnknown,
+		History: make([]PricePointDTO, 0, min(len(points), limit)),
+	}
+
+	sum := decimal.Zero
+	unitPrices := make([]decimal.Decimal, len(points))
+	for i, point := range points {
+		unitPrices[i] = point.Price.Div(decimal.NewFromFloat(point.Quantity))
+		sum = sum.Add(unitPrices[i])
+
+		if i < limit {
+			summary.History = append(summary.History, PricePointDTO{
+				Quantity:   point.Quantity,
+				Price:      point.Price,
+				UnitPrice:  unitPrices[i].Round(2),
+				RecordedAt: point.RecordedAt,
+			})
+		}
+	}
+
+	summary.Min = decimal.Min(unitPrices[0], unitPrices[1:]...).Round(2)
+	summary.Max = decimal.Max(unitPrices[0], unitPrices[1:]...).Round(2)
+	summary.Avg = sum.Div(decimal.NewFromInt(int64(len(points)))).Round(2)
+	summary.Last = unitPrices[0].Round(2)
+
+	if len(points) < 2 {
+		return summary
+	}
+
+	window := unitPrices[1:min(len(unitPrices), trendWindow+1)]
+	previous := decimal.Avg(window[0], window[1:]...)
+
+	if previous.IsZero() {
+		return summary
+	}
+
+	summary.Change = unitPrices[0].Sub(previous).Div(previous).Mul(decimal.NewFromInt(100)).Round(2).InexactFloat64()
+
+	switch {
+	case summary.Change > trendThreshold:
+		summary.Trend = TrendUp
+	case summary.Change < -trendThreshold:
+		summary.Trend = TrendDown
+	default:
+		summary.Trend = TrendStable
+	}
+
+	return summary
+}
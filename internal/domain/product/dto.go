@@ -1,14 +1,144 @@
 package product
 
-import "time"
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
 
 type ProductDTO struct {
-	ProductID   uint64    `json:"product_id" db:"product_id"`
-	ProductName string    `json:"product_name" db:"product_name"`
-	Category    string    `json:"category" db:"category_name"`
-	Price       *float64  `json:"price" db:"price"`
-	Quantity    int       `json:"quantity" db:"quantity"`
-	AddedBy     string    `json:"added_by" db:"added_by"`
-	BoughtBy    *string   `json:"bought_by" db:"bought_by"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ProductID     uint64           `json:"product_id" db:"product_id"`
+	ProductNameID uint64           `json:"product_name_id" db:"product_name_id"`
+	ProductName   string           `json:"product_name" db:"product_name"`
+	CategoryID    uint64           `json:"category_id" db:"category_id"`
+	Category      string           `json:"category" db:"category_name"`
+	Status        string           `json:"status" db:"status"`
+	Price         *decimal.Decimal `json:"price" db:"price"`
+	Currency      string           `json:"currency" db:"currency"`
+	Quantity      float64          `json:"quantity" db:"quantity"`
+	Unit          string           `json:"unit" db:"unit"`
+	AddedByID     uint64           `json:"added_by_id" db:"added_by_id"`
+	AddedBy       string           `json:"added_by" db:"added_by"`
+	BoughtByID    *uint64          `json:"bought_by_id" db:"bought_by_id"`
+	BoughtBy      *string          `json:"bought_by" db:"bought_by"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	Note          string           `json:"note" db:"note"`
+	Priority      string           `json:"priority" db:"priority"`
+	NeededBy      *time.Time       `json:"needed_by" db:"needed_by"`
+	AssignedToID  *uint64          `json:"assigned_to_id" db:"assigned_to_id"`
+	AssignedTo    *string          `json:"assigned_to" db:"assigned_to"`
+	ClaimedAt     *time.Time       `json:"claimed_at" db:"claimed_at"`
+	BoughtAt      *time.Time       `json:"bought_at" db:"bought_at"`
+	Rank          decimal.Decimal  `json:"rank" db:"rank"`
+	// SectionID, Section and SectionPosition place the product in the store of the listing
+	SectionID       *uint64 `json:"section_id" db:"section_id"`
+	Section         *string `json:"section" db:"section_name"`
+	SectionPosition *int    `json:"section_position" db:"section_position"`
+	// GroupPrice is the price in the currency of the group, nil without a price or an exchange rate
+	GroupPrice *decimal.Decimal `json:"group_price" db:"group_price"`
+}
+
+// ProductFilter narrows and orders the product listing of a group, zero values mean no filter
+type ProductFilter struct {
+	Status       string
+	CategoryID   uint64
+	AddedBy      uint64
+	BoughtBy     uint64
+	AssignedTo   uint64
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	Query        string
+	Priority     string
+	NeededBefore *time.Time
+	// StoreID places the products in the sections of the store
+	StoreID uint64
+	Sort    string
+	Cursor  string
+	Limit   int
+}
+
+// CursorTimestampLayout keeps the microseconds of a timestamp stored in a cursor
+const CursorTimestampLayout = "2006-01-02 15:04:05.999999"
+
+// SortTypes are the types the cursor values of each sort of the product listing are cast to
+var SortTypes = map[string][]string{
+	"rank":       {"numeric"},
+	"created_at": {"timestamp"},
+	"category":   {"text", "text"},
+	"name":       {"text"},
+	"price":      {"numeric"},
+	"priority":   {"int", "timestamp"},
+	"store":      {"int", "text", "text"},
+	"needed_by":  {"date", "timestamp"},
+}
+
+// ProductCursor is the position right after the last product of a page, Values are
+// the sort keys of that product in the order of the sort
+type ProductCursor struct {
+	Sort      string   `json:"s"`
+	Values    []string `json:"v"`
+	ProductID uint64   `json:"id"`
+}
+
+type ProductPageDTO struct {
+	Products   []ProductDTO
+	NextCursor string
+}
+
+type ProductRequestDTO struct {
+	Username  string    `json:"username" db:"username"`
+	Quantity  float64   `json:"quantity" db:"quantity"`
+	Unit      string    `json:"unit" db:"unit"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type CategoryProductsDTO struct {
+	CategoryID uint64
+	GroupID    uint64
+	UserID     uint64
+	Locales    []string
+}
+
+type CreateCategoryDTO struct {
+	Name string
+}
+
+type UpdateCategoryDTO struct {
+	CategoryID uint64
+	Name       string
+}
+
+type CreateProductNameDTO struct {
+	CategoryID  uint64
+	Name        string
+	DefaultUnit string
+}
+
+type UpdateProductNameDTO struct {
+	ProductNameID uint64
+	CategoryID    uint64
+	Name          string
+	DefaultUnit   string
+}
+
+type MergeDTO struct {
+	SourceID uint64
+	TargetID uint64
+}
+
+type SearchDTO struct {
+	UserID  uint64
+	GroupID uint64
+	Query   string
+	Locales []string
+	Limit   int
+	Offset  int
+}
+
+type SearchResultDTO struct {
+	ProductNameID uint64  `json:"product_name_id" db:"product_name_id"`
+	CategoryID    uint64  `json:"category_id" db:"category_id"`
+	Name          string  `json:"name" db:"name"`
+	GroupID       *uint64 `json:"group_id" db:"group_id"`
+	Popularity    int     `json:"popularity" db:"popularity"`
+	Score         float64 `json:"score" db:"score"`
 }
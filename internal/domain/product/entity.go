@@ -1,17 +1,48 @@
 package product
 
-import "time"
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityUrgent = "urgent"
+)
 
 type Product struct {
 	ProductID     uint64
 	GroupID       uint64
 	ProductNameID uint64
-	Price         *float64
+	Price         *decimal.Decimal
 	Status        string
-	Quantity      int
+	Quantity      float64
+	Unit          string
 	AddedBy       uint64
 	BoughtBy      *uint64
 	CreatedAt     time.Time
+	Note          string
+	Priority      string
+	NeededBy      *time.Time
+	AssignedTo    *uint64
+	ClaimedAt     *time.Time
+	TripID        *uint64
+	BoughtAt      *time.Time
+	// Currency of the price, the currency of the group when nil
+	Currency *string
+	// Rank orders the products of the group by hand
+	Rank decimal.Decimal
+}
+
+// ProductRequest is the quantity of a product asked by a member, a merged product keeps all of them
+type ProductRequest struct {
+	ProductRequestID uint64
+	ProductID        uint64
+	UserID           uint64
+	Quantity         float64
+	Unit             string
+	CreatedAt        time.Time
 }
 
 type Category struct {
@@ -20,7 +51,21 @@ type Category struct {
 }
 
 type ProductName struct {
-	ProductNameID uint64 `json:"product_name_id" db:"product_name_id"`
-	CategoryID    uint64 `json:"category_id" db:"category_id"`
-	Name          string `json:"name" db:"name"`
+	ProductNameID uint64  `json:"product_name_id" db:"product_name_id"`
+	CategoryID    uint64  `json:"category_id" db:"category_id"`
+	Name          string  `json:"name" db:"name"`
+	GroupID       *uint64 `json:"group_id" db:"group_id"`
+	DefaultUnit   string  `json:"default_unit" db:"default_unit"`
+}
+
+type Translation struct {
+	ID   uint64
+	Name string
+}
+
+// Translations are the names of categories and product names of one locale by their ids
+type Translations struct {
+	Locale       string
+	Categories   []Translation
+	ProductNames []Translation
 }
@@ -1,31 +1,78 @@
 package product
 
 import (
+	"cmp"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
 	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/pkg/locale"
+	"github.com/tclutin/shoppinglist-api/pkg/translit"
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+// uniqueViolation is the Postgres code of a duplicate key error
+const uniqueViolation = "23505"
+
 type Repository interface {
 	Create(ctx context.Context, product Product) (uint64, error)
 	Update(ctx context.Context, product Product) error
+	UpdateToPantry(ctx context.Context, product Product, expiresAt *time.Time) error
 	Delete(ctx context.Context, productID uint64) error
 	GetById(ctx context.Context, productID uint64) (Product, error)
-	GetCategories(ctx context.Context) ([]Category, error)
-	GetGroupProducts(ctx context.Context, groupID uint64) ([]ProductDTO, error)
-	GetProductsByCategoryId(ctx context.Context, categoryID uint64) ([]ProductName, error)
+	GetCategories(ctx context.Context, locales []string) ([]Category, error)
+	GetGroupProducts(ctx context.Context, groupID uint64, locales []string, filter ProductFilter, after *ProductCursor) ([]ProductDTO, *ProductCursor, error)
+	GetProductsByCategoryId(ctx context.Context, categoryID uint64, groupID uint64, locales []string) ([]ProductName, error)
 	GetByProductNameId(ctx context.Context, productNameID uint64) (ProductName, error)
+	GetProductNameByName(ctx context.Context, groupID uint64, name string) (ProductName, error)
+	CreateProductName(ctx context.Context, productName ProductName) (uint64, error)
+	GetGroupProductNames(ctx context.Context, groupID uint64) ([]ProductName, error)
+	GetCategoryById(ctx context.Context, categoryID uint64) (Category, error)
+
+	CreateCategory(ctx context.Context, category Category) (uint64, error)
+	UpdateCategory(ctx context.Context, category Category) error
+	DeleteCategory(ctx context.Context, categoryID uint64) error
+	CountProductNamesByCategoryId(ctx context.Context, categoryID uint64) (int, error)
+	MergeCategories(ctx context.Context, sourceID uint64, targetID uint64) error
+	UpdateProductName(ctx context.Context, productName ProductName) error
+	DeleteProductName(ctx context.Context, productNameID uint64) error
+	CountProductNameUses(ctx context.Context, productNameID uint64) (int, error)
+	MergeProductNames(ctx context.Context, sourceID uint64, targetID uint64) error
+	ImportTranslations(ctx context.Context, translations Translations) (int, error)
+	Search(ctx context.Context, userID uint64, groupID uint64, variants []string, locales []string, limit int, offset int) ([]SearchResultDTO, error)
+
+	Assign(ctx context.Context, productID uint64, assignedTo *uint64, claimedAt *time.Time) error
+	Move(ctx context.Context, productID uint64, anchorID uint64, after bool) error
+	CreateOrMerge(ctx context.Context, product Product) (uint64, bool, error)
+	MergeProducts(ctx context.Context, groupID uint64, targetID uint64, sourceIDs []uint64) (int, error)
+	GetOpenProductsByGroupId(ctx context.Context, groupID uint64) ([]Product, error)
+	GetProductRequests(ctx context.Context, productID uint64) ([]ProductRequestDTO, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
 }
 
 type Service struct {
-	repo Repository
+	repo       Repository
+	memberRepo MemberRepository
 }
 
-func NewService(repo Repository) *Service {
+func NewService(repo Repository, memberRepo MemberRepository) *Service {
 	return &Service{
-		repo: repo,
+		repo:       repo,
+		memberRepo: memberRepo,
 	}
 }
 
@@ -33,10 +80,94 @@ func (s *Service) Create(ctx context.Context, product Product) (uint64, error) {
 	return s.repo.Create(ctx, product)
 }
 
+// CreateOrMerge adds the quantity of the product to an open product of the group with the
+// same name and a compatible unit. The product is created when there's nothing to merge into
+func (s *Service) CreateOrMerge(ctx context.Context, product Product) (uint64, bool, error) {
+	productID, merged, err := s.repo.CreateOrMerge(ctx, product)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to merge product: %w", err)
+	}
+
+	return productID, merged, nil
+}
+
+// Dedupe merges the open products of the group with the same name and compatible units
+// into the oldest one and returns the number of removed duplicates
+func (s *Service) Dedupe(ctx context.Context, groupID uint64) (int, error) {
+	products, err := s.repo.GetOpenProductsByGroupId(ctx, groupID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get open products: %w", err)
+	}
+
+	merged := make(map[uint64]bool, len(products))
+	removed := 0
+
+	for i, target := range products {
+		if merged[target.ProductID] {
+			continue
+		}
+
+		var sourceIDs []uint64
+		for _, source := range products[i+1:] {
+			if merged[source.ProductID] || source.ProductNameID != target.ProductNameID {
+				continue
+			}
+
+			if _, ok := unit.Convert(source.Quantity, source.Unit, target.Unit); !ok {
+				continue
+			}
+
+			sourceIDs = append(sourceIDs, source.ProductID)
+			merged[source.ProductID] = true
+		}
+
+		if len(sourceIDs) == 0 {
+			continue
+		}
+
+		// the repository reads the quantities again, the products may have changed since
+		count, err := s.repo.MergeProducts(ctx, groupID, target.ProductID, sourceIDs)
+		if err != nil {
+			return removed, fmt.Errorf("failed to merge products: %w", err)
+		}
+
+		removed += count
+	}
+
+	return removed, nil
+}
+
+func (s *Service) GetProductRequests(ctx context.Context, productID uint64) ([]ProductRequestDTO, error) {
+	return s.repo.GetProductRequests(ctx, productID)
+}
+
 func (s *Service) Update(ctx context.Context, product Product) error {
 	return s.repo.Update(ctx, product)
 }
 
+// UpdateToPantry saves the bought product together with its stock in the pantry of the group
+func (s *Service) UpdateToPantry(ctx context.Context, product Product, expiresAt *time.Time) error {
+	return s.repo.UpdateToPantry(ctx, product, expiresAt)
+}
+
+func (s *Service) Assign(ctx context.Context, productID uint64, assignedTo *uint64, claimedAt *time.Time) error {
+	return s.repo.Assign(ctx, productID, assignedTo, claimedAt)
+}
+
+// Move puts the product right after the anchor or right before it
+func (s *Service) Move(ctx context.Context, productID uint64, anchorID uint64, after bool) error {
+	err := s.repo.Move(ctx, productID, anchorID, after)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrProductNotFound
+		}
+
+		return fmt.Errorf("failed to move product: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) RemoveProduct(ctx context.Context, productID uint64) error {
 	_, err := s.repo.GetById(ctx, productID)
 	if err != nil {
@@ -76,14 +207,365 @@ func (s *Service) GetByProductNameId(ctx context.Context, productNameID uint64)
 	return productName, nil
 }
 
-func (s *Service) GetGroupProducts(ctx context.Context, groupID uint64) ([]ProductDTO, error) {
-	return s.repo.GetGroupProducts(ctx, groupID)
+// GetGroupProducts returns a page of the group products and the cursor of the next page,
+// the cursor is empty on the last page
+func (s *Service) GetGroupProducts(ctx context.Context, groupID uint64, locales []string, filter ProductFilter) (ProductPageDTO, error) {
+	filter.Sort = cmp.Or(filter.Sort, "rank")
+	filter.Query = escapeLike(strings.TrimSpace(filter.Query))
+
+	var after *ProductCursor
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter.Cursor)
+		if err != nil || cursor.Sort != filter.Sort || !isValidCursor(cursor) {
+			return ProductPageDTO{}, domainErr.ErrInvalidCursor
+		}
+
+		after = &cursor
+	}
+
+	products, next, err := s.repo.GetGroupProducts(ctx, groupID, locales, filter, after)
+	if err != nil {
+		return ProductPageDTO{}, err
+	}
+
+	page := ProductPageDTO{Products: products}
+	if next != nil {
+		page.NextCursor, err = encodeCursor(*next)
+		if err != nil {
+			return ProductPageDTO{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// isValidCursor checks that the cursor has a value of the right type for each key of its sort,
+// so the repository never gets a value the database can't cast
+func isValidCursor(cursor ProductCursor) bool {
+	types, ok := SortTypes[cursor.Sort]
+	if !ok || len(cursor.Values) != len(types) {
+		return false
+	}
+
+	for i, value := range cursor.Values {
+		var err error
+
+		switch types[i] {
+		case "numeric":
+			if value != "NaN" {
+				_, err = decimal.NewFromString(value)
+			}
+		case "int":
+			_, err = strconv.ParseInt(value, 10, 32)
+		case "timestamp":
+			_, err = time.Parse(CursorTimestampLayout, value)
+		case "date":
+			if value != "infinity" {
+				_, err = time.Parse(time.DateOnly, value)
+			}
+		case "text":
+			if !utf8.ValidString(value) || strings.ContainsRune(value, 0) {
+				return false
+			}
+		}
+
+		if err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func encodeCursor(cursor ProductCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(text string) (ProductCursor, error) {
+	var cursor ProductCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return cursor, err
+	}
+
+	err = json.Unmarshal(data, &cursor)
+
+	return cursor, err
+}
+
+func (s *Service) GetCategories(ctx context.Context, locales []string) ([]Category, error) {
+	return s.repo.GetCategories(ctx, locales)
 }
 
-func (s *Service) GetCategories(ctx context.Context) ([]Category, error) {
-	return s.repo.GetCategories(ctx)
+func (s *Service) GetProductNameByName(ctx context.Context, groupID uint64, name string) (ProductName, error) {
+	productName, err := s.repo.GetProductNameByName(ctx, groupID, name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return productName, domainErr.ErrProductNotFound
+		}
+
+		return productName, fmt.Errorf("failed to get product name: %w", err)
+	}
+
+	return productName, nil
+}
+
+func (s *Service) CreateProductName(ctx context.Context, productName ProductName) (uint64, error) {
+	productNameID, err := s.repo.CreateProductName(ctx, productName)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return 0, domainErr.ErrProductNameAlreadyExists
+		}
+
+		return 0, err
+	}
+
+	return productNameID, nil
+}
+
+func (s *Service) GetGroupProductNames(ctx context.Context, groupID uint64) ([]ProductName, error) {
+	return s.repo.GetGroupProductNames(ctx, groupID)
+}
+
+func (s *Service) GetCategoryById(ctx context.Context, categoryID uint64) (Category, error) {
+	category, err := s.repo.GetCategoryById(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return category, domainErr.ErrCategoryNotFound
+		}
+
+		return category, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return category, nil
 }
 
-func (s *Service) GetProductsByCategoryId(ctx context.Context, categoryID uint64) ([]ProductName, error) {
-	return s.repo.GetProductsByCategoryId(ctx, categoryID)
+// GetProductsByCategoryId returns the global product names of the category and,
+// when a group is given, the group's own names as well
+func (s *Service) GetProductsByCategoryId(ctx context.Context, dto CategoryProductsDTO) ([]ProductName, error) {
+	if dto.GroupID != 0 {
+		_, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, dto.GroupID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, domainErr.ErrMemberNotFound
+			}
+
+			return nil, fmt.Errorf("failed to get member: %w", err)
+		}
+	}
+
+	return s.repo.GetProductsByCategoryId(ctx, dto.CategoryID, dto.GroupID, dto.Locales)
+}
+
+func (s *Service) CreateCategory(ctx context.Context, dto CreateCategoryDTO) (uint64, error) {
+	return s.repo.CreateCategory(ctx, Category{Name: strings.TrimSpace(dto.Name)})
+}
+
+func (s *Service) UpdateCategory(ctx context.Context, dto UpdateCategoryDTO) error {
+	category, err := s.GetCategoryById(ctx, dto.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	category.Name = strings.TrimSpace(dto.Name)
+
+	return s.repo.UpdateCategory(ctx, category)
+}
+
+// DeleteCategory removes an empty category. Categories with product names have to be merged instead,
+// otherwise the products of the groups would be deleted by cascade
+func (s *Service) DeleteCategory(ctx context.Context, categoryID uint64) error {
+	category, err := s.GetCategoryById(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.repo.CountProductNamesByCategoryId(ctx, category.CategoryID)
+	if err != nil {
+		return fmt.Errorf("failed to count product names: %w", err)
+	}
+
+	if count > 0 {
+		return domainErr.ErrCategoryInUse
+	}
+
+	return s.repo.DeleteCategory(ctx, category.CategoryID)
+}
+
+// MergeCategories moves all product names of the source category to the target one and deletes the source
+func (s *Service) MergeCategories(ctx context.Context, dto MergeDTO) error {
+	if dto.SourceID == dto.TargetID {
+		return domainErr.ErrCannotMergeIntoItself
+	}
+
+	source, err := s.GetCategoryById(ctx, dto.SourceID)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.GetCategoryById(ctx, dto.TargetID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.MergeCategories(ctx, source.CategoryID, target.CategoryID)
+}
+
+func (s *Service) CreateGlobalProductName(ctx context.Context, dto CreateProductNameDTO) (uint64, error) {
+	category, err := s.GetCategoryById(ctx, dto.CategoryID)
+	if err != nil {
+		return 0, err
+	}
+
+	defaultUnit := dto.DefaultUnit
+	if defaultUnit == "" {
+		defaultUnit = unit.Pieces
+	}
+
+	if !unit.IsValid(defaultUnit) {
+		return 0, domainErr.ErrInvalidUnit
+	}
+
+	name := strings.TrimSpace(dto.Name)
+
+	_, err = s.GetProductNameByName(ctx, 0, name)
+	if err == nil {
+		return 0, domainErr.ErrProductNameAlreadyExists
+	}
+
+	if !errors.Is(err, domainErr.ErrProductNotFound) {
+		return 0, err
+	}
+
+	return s.repo.CreateProductName(ctx, ProductName{
+		CategoryID:  category.CategoryID,
+		Name:        name,
+		DefaultUnit: defaultUnit,
+	})
+}
+
+func (s *Service) UpdateProductName(ctx context.Context, dto UpdateProductNameDTO) error {
+	productName, err := s.GetByProductNameId(ctx, dto.ProductNameID)
+	if err != nil {
+		return err
+	}
+
+	if dto.CategoryID != 0 {
+		category, err := s.GetCategoryById(ctx, dto.CategoryID)
+		if err != nil {
+			return err
+		}
+
+		productName.CategoryID = category.CategoryID
+	}
+
+	if dto.DefaultUnit != "" {
+		if !unit.IsValid(dto.DefaultUnit) {
+			return domainErr.ErrInvalidUnit
+		}
+
+		productName.DefaultUnit = dto.DefaultUnit
+	}
+
+	if name := strings.TrimSpace(dto.Name); name != "" && !strings.EqualFold(name, productName.Name) {
+		var groupID uint64
+		if productName.GroupID != nil {
+			groupID = *productName.GroupID
+		}
+
+		_, err = s.GetProductNameByName(ctx, groupID, name)
+		if err == nil {
+			return domainErr.ErrProductNameAlreadyExists
+		}
+
+		if !errors.Is(err, domainErr.ErrProductNotFound) {
+			return err
+		}
+
+		productName.Name = name
+	}
+
+	return s.repo.UpdateProductName(ctx, productName)
+}
+
+// DeleteProductName removes a product name which isn't used by any product, price, or anything
+// else of the groups. Used product names have to be merged into another one instead
+func (s *Service) DeleteProductName(ctx context.Context, productNameID uint64) error {
+	productName, err := s.GetByProductNameId(ctx, productNameID)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.repo.CountProductNameUses(ctx, productName.ProductNameID)
+	if err != nil {
+		return fmt.Errorf("failed to count product name uses: %w", err)
+	}
+
+	if count > 0 {
+		return domainErr.ErrProductNameInUse
+	}
+
+	return s.repo.DeleteProductName(ctx, productName.ProductNameID)
+}
+
+// MergeProductNames re-points all products of the source product name to the target one and deletes the source
+func (s *Service) MergeProductNames(ctx context.Context, dto MergeDTO) error {
+	if dto.SourceID == dto.TargetID {
+		return domainErr.ErrCannotMergeIntoItself
+	}
+
+	source, err := s.GetByProductNameId(ctx, dto.SourceID)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.GetByProductNameId(ctx, dto.TargetID)
+	if err != nil {
+		return err
+	}
+
+	if target.GroupID != nil && (source.GroupID == nil || *source.GroupID != *target.GroupID) {
+		return domainErr.ErrForeignProductName
+	}
+
+	return s.repo.MergeProductNames(ctx, source.ProductNameID, target.ProductNameID)
+}
+
+// Search looks up product names by the query typed as is and transliterated in both directions
+func (s *Service) Search(ctx context.Context, dto SearchDTO) ([]SearchResultDTO, error) {
+	query := strings.ToLower(strings.Join(strings.Fields(dto.Query), " "))
+	if query == "" {
+		return []SearchResultDTO{}, nil
+	}
+
+	variants := []string{query, translit.ToCyrillic(query), translit.ToLatin(query)}
+	slices.Sort(variants)
+	variants = slices.Compact(variants)
+
+	for i := range variants {
+		variants[i] = escapeLike(variants[i])
+	}
+
+	return s.repo.Search(ctx, dto.UserID, dto.GroupID, variants, dto.Locales, dto.Limit, dto.Offset)
+}
+
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
+
+// ImportTranslations adds or replaces the names of a locale, unknown ids are skipped
+func (s *Service) ImportTranslations(ctx context.Context, translations Translations) (int, error) {
+	translations.Locale = locale.Normalize(translations.Locale)
+	if !locale.IsValid(translations.Locale) {
+		return 0, domainErr.ErrInvalidLocale
+	}
+
+	return s.repo.ImportTranslations(ctx, translations)
 }
@@ -0,0 +1,78 @@
+package recipe
+
+import (
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"time"
+)
+
+type NewIngredientDTO struct {
+	ProductNameID uint64
+	Quantity      float64
+	Unit          string
+	Note          string
+}
+
+type CreateRecipeDTO struct {
+	GroupID     uint64
+	UserID      uint64
+	Name        string
+	Servings    int
+	Ingredients []NewIngredientDTO
+}
+
+// UpdateRecipeDTO changes only the given fields and replaces the ingredients when Ingredients is not nil
+type UpdateRecipeDTO struct {
+	GroupID     uint64
+	UserID      uint64
+	RecipeID    uint64
+	Name        string
+	Servings    int
+	Ingredients []NewIngredientDTO
+}
+
+type RecipeUserDTO struct {
+	GroupID  uint64
+	UserID   uint64
+	RecipeID uint64
+	Locales  []string
+}
+
+type GroupRecipesDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+}
+
+// AddToListDTO adds the ingredients for Servings to the list, the servings of the recipe when zero
+type AddToListDTO struct {
+	GroupID  uint64
+	UserID   uint64
+	RecipeID uint64
+	Servings int
+}
+
+type IngredientDTO struct {
+	IngredientID  uint64  `json:"ingredient_id" db:"ingredient_id"`
+	RecipeID      uint64  `json:"-" db:"recipe_id"`
+	ProductNameID uint64  `json:"product_name_id" db:"product_name_id"`
+	ProductName   string  `json:"product_name" db:"product_name"`
+	Quantity      float64 `json:"quantity" db:"quantity"`
+	Unit          string  `json:"unit" db:"unit"`
+	Note          string  `json:"note" db:"note"`
+}
+
+type RecipeDTO struct {
+	RecipeID    uint64          `json:"recipe_id" db:"recipe_id"`
+	Name        string          `json:"name" db:"name"`
+	Servings    int             `json:"servings" db:"servings"`
+	CreatedBy   uint64          `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	Ingredients []IngredientDTO `json:"ingredients" db:"-"`
+}
+
+// AddedRecipeDTO lists the products added or merged for the recipe, InPantry are the product names
+// the pantry of the group has enough of
+type AddedRecipeDTO struct {
+	Products []group.AddedProductDTO
+	InPantry []uint64
+}
@@ -0,0 +1,22 @@
+package recipe
+
+import "time"
+
+// Recipe of the group, the quantities of its ingredients are for Servings
+type Recipe struct {
+	RecipeID  uint64
+	GroupID   uint64
+	Name      string
+	Servings  int
+	CreatedBy uint64
+	CreatedAt time.Time
+}
+
+type Ingredient struct {
+	IngredientID  uint64
+	RecipeID      uint64
+	ProductNameID uint64
+	Quantity      float64
+	Unit          string
+	Note          string
+}
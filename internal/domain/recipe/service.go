@@ -0,0 +1,366 @@
+package recipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/pantry"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+	"math"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, recipe Recipe, ingredients []Ingredient) (uint64, error)
+	GetById(ctx context.Context, recipeID uint64) (Recipe, error)
+	GetIngredients(ctx context.Context, recipeID uint64) ([]Ingredient, error)
+	Update(ctx context.Context, recipe Recipe, ingredients []Ingredient) error
+	Delete(ctx context.Context, recipeID uint64) error
+	GetRecipes(ctx context.Context, groupID uint64) ([]RecipeDTO, error)
+	GetRecipeIngredients(ctx context.Context, recipeIDs []uint64, locales []string) ([]IngredientDTO, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type ProductRepository interface {
+	GetByProductNameId(ctx context.Context, productNameID uint64) (product.ProductName, error)
+}
+
+type PantryRepository interface {
+	GetByProductNameId(ctx context.Context, groupID uint64, productNameID uint64) ([]pantry.Item, error)
+}
+
+type GroupService interface {
+	AddProduct(ctx context.Context, dto group.CreateProductDTO) (group.AddedProductDTO, error)
+}
+
+type Service struct {
+	repo         Repository
+	groupRepo    GroupRepository
+	memberRepo   MemberRepository
+	productRepo  ProductRepository
+	pantryRepo   PantryRepository
+	groupService GroupService
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, productRepo ProductRepository, pantryRepo PantryRepository, groupService GroupService) *Service {
+	return &Service{
+		repo:         repo,
+		groupRepo:    groupRepo,
+		memberRepo:   memberRepo,
+		productRepo:  productRepo,
+		pantryRepo:   pantryRepo,
+		groupService: groupService,
+	}
+}
+
+func (s *Service) CreateRecipe(ctx context.Context, dto CreateRecipeDTO) (uint64, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	ingredients, err := s.newIngredients(ctx, group.GroupID, dto.Ingredients)
+	if err != nil {
+		return 0, err
+	}
+
+	recipe := Recipe{
+		GroupID:   group.GroupID,
+		Name:      dto.Name,
+		Servings:  dto.Servings,
+		CreatedBy: membr.UserID,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	return s.repo.Create(ctx, recipe, ingredients)
+}
+
+// GetRecipes returns the recipes of the group with their ingredients
+func (s *Service) GetRecipes(ctx context.Context, dto GroupRecipesDTO) ([]RecipeDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	recipes, err := s.repo.GetRecipes(ctx, group.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipes: %w", err)
+	}
+
+	return s.withIngredients(ctx, recipes, dto.Locales)
+}
+
+func (s *Service) GetRecipe(ctx context.Context, dto RecipeUserDTO) (RecipeDTO, error) {
+	recipe, err := s.getRecipe(ctx, dto)
+	if err != nil {
+		return RecipeDTO{}, err
+	}
+
+	recipes, err := s.withIngredients(ctx, []RecipeDTO{{
+		RecipeID:  recipe.RecipeID,
+		Name:      recipe.Name,
+		Servings:  recipe.Servings,
+		CreatedBy: recipe.CreatedBy,
+		CreatedAt: recipe.CreatedAt,
+	}}, dto.Locales)
+
+	if err != nil {
+		return RecipeDTO{}, err
+	}
+
+	return recipes[0], nil
+}
+
+func (s *Service) UpdateRecipe(ctx context.Context, dto UpdateRecipeDTO) error {
+	recipe, err := s.getRecipe(ctx, RecipeUserDTO{
+		GroupID:  dto.GroupID,
+		UserID:   dto.UserID,
+		RecipeID: dto.RecipeID,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if dto.Name != "" {
+		recipe.Name = dto.Name
+	}
+
+	if dto.Servings != 0 {
+		recipe.Servings = dto.Servings
+	}
+
+	var ingredients []Ingredient
+	if dto.Ingredients != nil {
+		ingredients, err = s.newIngredients(ctx, recipe.GroupID, dto.Ingredients)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Update(ctx, recipe, ingredients)
+}
+
+func (s *Service) DeleteRecipe(ctx context.Context, dto RecipeUserDTO) error {
+	recipe, err := s.getRecipe(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, recipe.RecipeID)
+}
+
+// AddToList adds the ingredients scaled to the servings to the group like group.Service.AddProduct does,
+// always merging with the open products. What the pantry of the group has in a compatible unit is
+// subtracted first. The ingredients are added one by one, so an error can leave the recipe added partly
+func (s *Service) AddToList(ctx context.Context, dto AddToListDTO) (AddedRecipeDTO, error) {
+	recipe, err := s.getRecipe(ctx, RecipeUserDTO{
+		GroupID:  dto.GroupID,
+		UserID:   dto.UserID,
+		RecipeID: dto.RecipeID,
+	})
+
+	if err != nil {
+		return AddedRecipeDTO{}, err
+	}
+
+	ingredients, err := s.repo.GetIngredients(ctx, recipe.RecipeID)
+	if err != nil {
+		return AddedRecipeDTO{}, fmt.Errorf("failed to get ingredients: %w", err)
+	}
+
+	servings := dto.Servings
+	if servings == 0 {
+		servings = recipe.Servings
+	}
+
+	scale := float64(servings) / float64(recipe.Servings)
+
+	// stock left in the pantry by pantry item, an ingredient can use the stock another one has used
+	stock := make(map[uint64]float64)
+
+	merge := true
+	added := AddedRecipeDTO{
+		Products: make([]group.AddedProductDTO, 0, len(ingredients)),
+		InPantry: make([]uint64, 0),
+	}
+
+	for _, ingredient := range ingredients {
+		quantity := ingredient.Quantity * scale
+
+		items, err := s.pantryRepo.GetByProductNameId(ctx, recipe.GroupID, ingredient.ProductNameID)
+		if err != nil {
+			return added, fmt.Errorf("failed to get pantry items: %w", err)
+		}
+
+		for _, item := range items {
+			left, ok := stock[item.PantryItemID]
+			if !ok {
+				left = item.Quantity
+			}
+
+			available, ok := unit.Convert(left, item.Unit, ingredient.Unit)
+			if !ok || available <= 0 {
+				continue
+			}
+
+			used := min(available, quantity)
+			quantity -= used
+
+			left, _ = unit.Convert(available-used, ingredient.Unit, item.Unit)
+			stock[item.PantryItemID] = left
+		}
+
+		// quantities are kept with three decimals
+		quantity = math.Round(quantity*1000) / 1000
+		if quantity <= 0 {
+			added.InPantry = append(added.InPantry, ingredient.ProductNameID)
+			continue
+		}
+
+		product, err := s.groupService.AddProduct(ctx, group.CreateProductDTO{
+			UserID:        dto.UserID,
+			GroupID:       recipe.GroupID,
+			ProductNameID: ingredient.ProductNameID,
+			Quantity:      quantity,
+			Unit:          ingredient.Unit,
+			Note:          ingredient.Note,
+			Merge:         &merge,
+		})
+
+		if err != nil {
+			return added, err
+		}
+
+		added.Products = append(added.Products, product)
+	}
+
+	return added, nil
+}
+
+func (s *Service) withIngredients(ctx context.Context, recipes []RecipeDTO, locales []string) ([]RecipeDTO, error) {
+	recipeIDs := make([]uint64, 0, len(recipes))
+	for _, recipe := range recipes {
+		recipeIDs = append(recipeIDs, recipe.RecipeID)
+	}
+
+	ingredients, err := s.repo.GetRecipeIngredients(ctx, recipeIDs, locales)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingredients: %w", err)
+	}
+
+	byRecipe := make(map[uint64][]IngredientDTO, len(recipes))
+	for _, ingredient := range ingredients {
+		byRecipe[ingredient.RecipeID] = append(byRecipe[ingredient.RecipeID], ingredient)
+	}
+
+	for i := range recipes {
+		recipes[i].Ingredients = byRecipe[recipes[i].RecipeID]
+		if recipes[i].Ingredients == nil {
+			recipes[i].Ingredients = []IngredientDTO{}
+		}
+	}
+
+	return recipes, nil
+}
+
+// getRecipe returns the recipe of the group, if the user is a member of the group
+func (s *Service) getRecipe(ctx context.Context, dto RecipeUserDTO) (Recipe, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Recipe{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Recipe{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	recipe, err := s.repo.GetById(ctx, dto.RecipeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Recipe{}, domainErr.ErrRecipeNotFound
+		}
+
+		return Recipe{}, fmt.Errorf("failed to get recipe: %w", err)
+	}
+
+	if recipe.GroupID != group.GroupID {
+		return Recipe{}, domainErr.ErrRecipeNotFound
+	}
+
+	return recipe, nil
+}
+
+// newIngredients checks that the product names are visible to the group and fills in the default units
+func (s *Service) newIngredients(ctx context.Context, groupID uint64, dtos []NewIngredientDTO) ([]Ingredient, error) {
+	ingredients := make([]Ingredient, 0, len(dtos))
+
+	for _, dto := range dtos {
+		productName, err := s.productRepo.GetByProductNameId(ctx, dto.ProductNameID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, domainErr.ErrProductNotFound
+			}
+
+			return nil, fmt.Errorf("failed to get product name: %w", err)
+		}
+
+		if productName.GroupID != nil && *productName.GroupID != groupID {
+			return nil, domainErr.ErrProductNotFound
+		}
+
+		ingredient := Ingredient{
+			ProductNameID: productName.ProductNameID,
+			Quantity:      dto.Quantity,
+			Unit:          dto.Unit,
+			Note:          dto.Note,
+		}
+
+		if ingredient.Unit == "" {
+			ingredient.Unit = productName.DefaultUnit
+		}
+
+		if !unit.IsValid(ingredient.Unit) {
+			return nil, domainErr.ErrInvalidUnit
+		}
+
+		ingredients = append(ingredients, ingredient)
+	}
+
+	return ingredients, nil
+}
@@ -0,0 +1,55 @@
+package recurring
+
+import "time"
+
+type CreateRuleDTO struct {
+	GroupID       uint64
+	UserID        uint64
+	ProductNameID uint64
+	Quantity      float64
+	Unit          string
+	Note          string
+	Cron          string
+	EveryDays     int
+}
+
+// UpdateRuleDTO changes only the given fields, a new Cron or EveryDays replaces the schedule
+type UpdateRuleDTO struct {
+	GroupID            uint64
+	UserID             uint64
+	RecurringProductID uint64
+	Quantity           float64
+	Unit               string
+	Note               *string
+	Cron               string
+	EveryDays          int
+}
+
+type RuleUserDTO struct {
+	GroupID            uint64
+	UserID             uint64
+	RecurringProductID uint64
+}
+
+type GroupRulesDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+}
+
+// RuleDTO is the rule with the status of the product it has put on the list last
+type RuleDTO struct {
+	RecurringProductID uint64     `json:"recurring_product_id" db:"recurring_product_id"`
+	ProductNameID      uint64     `json:"product_name_id" db:"product_name_id"`
+	ProductName        string     `json:"product_name" db:"product_name"`
+	Quantity           float64    `json:"quantity" db:"quantity"`
+	Unit               string     `json:"unit" db:"unit"`
+	Note               string     `json:"note" db:"note"`
+	Cron               *string    `json:"cron" db:"cron"`
+	EveryDays          *int       `json:"every_days" db:"every_days"`
+	ProductID          *uint64    `json:"product_id" db:"product_id"`
+	ProductStatus      *string    `json:"product_status" db:"product_status"`
+	NextRunAt          *time.Time `json:"next_run_at" db:"next_run_at"`
+	CreatedBy          uint64     `json:"created_by" db:"created_by"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+}
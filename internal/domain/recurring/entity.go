@@ -0,0 +1,22 @@
+package recurring
+
+import "time"
+
+// Rule puts the product on the list of the group again on a schedule, either Cron or EveryDays is set.
+// Cron is a standard five field expression in UTC, EveryDays repeats the product that many days after
+// the last one is bought
+type Rule struct {
+	RecurringProductID uint64
+	GroupID            uint64
+	ProductNameID      uint64
+	Quantity           float64
+	Unit               string
+	Note               string
+	Cron               *string
+	EveryDays          *int
+	// ProductID is the product the rule has put on the list last
+	ProductID *uint64
+	NextRunAt *time.Time
+	CreatedBy uint64
+	CreatedAt time.Time
+}
@@ -0,0 +1,247 @@
+package recurring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/robfig/cron/v3"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, rule Rule) (uint64, error)
+	GetById(ctx context.Context, recurringProductID uint64) (Rule, error)
+	Update(ctx context.Context, rule Rule) error
+	Delete(ctx context.Context, recurringProductID uint64) error
+	GetRules(ctx context.Context, groupID uint64, locales []string) ([]RuleDTO, error)
+	CreateDue(ctx context.Context, now time.Time, next func(rule Rule, now time.Time) time.Time) (int, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type ProductRepository interface {
+	GetByProductNameId(ctx context.Context, productNameID uint64) (product.ProductName, error)
+}
+
+type Service struct {
+	repo        Repository
+	groupRepo   GroupRepository
+	memberRepo  MemberRepository
+	productRepo ProductRepository
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, productRepo ProductRepository) *Service {
+	return &Service{
+		repo:        repo,
+		groupRepo:   groupRepo,
+		memberRepo:  memberRepo,
+		productRepo: productRepo,
+	}
+}
+
+func (s *Service) CreateRule(ctx context.Context, dto CreateRuleDTO) (uint64, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	productName, err := s.productRepo.GetByProductNameId(ctx, dto.ProductNameID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrProductNotFound
+		}
+
+		return 0, fmt.Errorf("failed to get product name: %w", err)
+	}
+
+	if productName.GroupID != nil && *productName.GroupID != group.GroupID {
+		return 0, domainErr.ErrProductNotFound
+	}
+
+	rule := Rule{
+		GroupID:       group.GroupID,
+		ProductNameID: productName.ProductNameID,
+		Quantity:      dto.Quantity,
+		Unit:          dto.Unit,
+		Note:          dto.Note,
+		CreatedBy:     membr.UserID,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if rule.Unit == "" {
+		rule.Unit = productName.DefaultUnit
+	}
+
+	if !unit.IsValid(rule.Unit) {
+		return 0, domainErr.ErrInvalidUnit
+	}
+
+	if err = setSchedule(&rule, dto.Cron, dto.EveryDays, rule.CreatedAt); err != nil {
+		return 0, err
+	}
+
+	return s.repo.Create(ctx, rule)
+}
+
+// GetRules returns the recurring products of the group, the oldest first
+func (s *Service) GetRules(ctx context.Context, dto GroupRulesDTO) ([]RuleDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	return s.repo.GetRules(ctx, group.GroupID, dto.Locales)
+}
+
+func (s *Service) UpdateRule(ctx context.Context, dto UpdateRuleDTO) error {
+	rule, err := s.getGroupRule(ctx, RuleUserDTO{
+		GroupID:            dto.GroupID,
+		UserID:             dto.UserID,
+		RecurringProductID: dto.RecurringProductID,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if dto.Quantity != 0 {
+		rule.Quantity = dto.Quantity
+	}
+
+	if dto.Unit != "" {
+		if !unit.IsValid(dto.Unit) {
+			return domainErr.ErrInvalidUnit
+		}
+
+		rule.Unit = dto.Unit
+	}
+
+	if dto.Note != nil {
+		rule.Note = *dto.Note
+	}
+
+	if dto.Cron != "" || dto.EveryDays != 0 {
+		if err = setSchedule(&rule, dto.Cron, dto.EveryDays, time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Update(ctx, rule)
+}
+
+func (s *Service) DeleteRule(ctx context.Context, dto RuleUserDTO) error {
+	rule, err := s.getGroupRule(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, rule.RecurringProductID)
+}
+
+// CreateDue puts the products of the due rules on the lists of the groups and returns how many
+// products were added, it is run by the scheduler of the app
+func (s *Service) CreateDue(ctx context.Context) (int, error) {
+	return s.repo.CreateDue(ctx, time.Now().UTC(), nextRun)
+}
+
+// getGroupRule returns the rule of the group if the user is its member
+func (s *Service) getGroupRule(ctx context.Context, dto RuleUserDTO) (Rule, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Rule{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Rule{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	rule, err := s.repo.GetById(ctx, dto.RecurringProductID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Rule{}, domainErr.ErrRecurringProductNotFound
+		}
+
+		return Rule{}, fmt.Errorf("failed to get recurring product: %w", err)
+	}
+
+	if rule.GroupID != group.GroupID {
+		return Rule{}, domainErr.ErrRecurringProductNotFound
+	}
+
+	return rule, nil
+}
+
+// setSchedule replaces the schedule of the rule, exactly one of cronExpr and everyDays must be set.
+// A cron rule is first due at its next time, a rule repeated after buying is due at once
+func setSchedule(rule *Rule, cronExpr string, everyDays int, now time.Time) error {
+	if (cronExpr == "") == (everyDays == 0) || everyDays < 0 {
+		return domainErr.ErrInvalidSchedule
+	}
+
+	if cronExpr != "" {
+		schedule, err := cron.ParseStandard(cronExpr)
+		if err != nil {
+			return domainErr.ErrInvalidSchedule
+		}
+
+		next := schedule.Next(now)
+		rule.Cron, rule.EveryDays, rule.NextRunAt = &cronExpr, nil, &next
+
+		return nil
+	}
+
+	rule.Cron, rule.EveryDays, rule.NextRunAt = nil, &everyDays, &now
+
+	return nil
+}
+
+// nextRun returns when the rule is due after now. A rule repeated after buying is due when its
+// product is bought, the interval since now only matters when the product is removed from the list
+func nextRun(rule Rule, now time.Time) time.Time {
+	if rule.EveryDays != nil {
+		return now.AddDate(0, 0, *rule.EveryDays)
+	}
+
+	schedule, err := cron.ParseStandard(*rule.Cron)
+	if err != nil {
+		// the expression is checked before the rule is saved, so this only guards against a broken row
+		return now.AddDate(0, 0, 1)
+	}
+
+	return schedule.Next(now)
+}
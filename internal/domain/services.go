@@ -3,30 +3,86 @@ package domain
 import (
 	"github.com/tclutin/shoppinglist-api/internal/config"
 	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	"github.com/tclutin/shoppinglist-api/internal/domain/barcode"
+	"github.com/tclutin/shoppinglist-api/internal/domain/budget"
+	"github.com/tclutin/shoppinglist-api/internal/domain/currency"
+	"github.com/tclutin/shoppinglist-api/internal/domain/expense"
 	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/mealplan"
+	"github.com/tclutin/shoppinglist-api/internal/domain/pantry"
+	"github.com/tclutin/shoppinglist-api/internal/domain/price"
 	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"github.com/tclutin/shoppinglist-api/internal/domain/recipe"
+	"github.com/tclutin/shoppinglist-api/internal/domain/recurring"
+	"github.com/tclutin/shoppinglist-api/internal/domain/stats"
+	"github.com/tclutin/shoppinglist-api/internal/domain/store"
+	"github.com/tclutin/shoppinglist-api/internal/domain/suggestion"
+	"github.com/tclutin/shoppinglist-api/internal/domain/template"
+	"github.com/tclutin/shoppinglist-api/internal/domain/trip"
 	"github.com/tclutin/shoppinglist-api/internal/domain/user"
 	"github.com/tclutin/shoppinglist-api/internal/repository"
 	"github.com/tclutin/shoppinglist-api/pkg/jwt/manager"
 )
 
 type Services struct {
-	Auth    *auth.Service
-	User    *user.Service
-	Group   *group.Service
-	Product *product.Service
+	Auth       *auth.Service
+	User       *user.Service
+	Group      *group.Service
+	Product    *product.Service
+	Trip       *trip.Service
+	Expense    *expense.Service
+	Stats      *stats.Service
+	Price      *price.Service
+	Budget     *budget.Service
+	Currency   *currency.Service
+	Recurring  *recurring.Service
+	Template   *template.Service
+	Suggestion *suggestion.Service
+	Pantry     *pantry.Service
+	Recipe     *recipe.Service
+	MealPlan   *mealplan.Service
+	Store      *store.Service
+	Barcode    *barcode.Service
 }
 
 func NewServices(cfg *config.Config, tokenManager manager.Manager, repos *repository.Repository) *Services {
 	userService := user.NewService(repos.User)
 	authService := auth.NewService(cfg, userService, tokenManager, repos.Session)
-	productService := product.NewService(repos.Product)
-	groupService := group.NewService(repos.Group, repos.Member, productService)
+	productService := product.NewService(repos.Product, repos.Member)
+	priceService := price.NewService(repos.Price, repos.Group, repos.Member, repos.Product)
+	budgetService := budget.NewService(repos.Budget, repos.Group, repos.Member, repos.Product)
+	groupService := group.NewService(repos.Group, repos.Member, productService, priceService, budgetService, repos.Currency, repos.Store)
+	tripService := trip.NewService(repos.Trip, repos.Group, repos.Member, repos.Currency, budgetService)
+	expenseService := expense.NewService(repos.Expense, repos.Group, repos.Member, repos.Product, repos.Currency)
+	statsService := stats.NewService(repos.Stats, repos.Group, repos.Member)
+	currencyService := currency.NewService(repos.Currency)
+	recurringService := recurring.NewService(repos.Recurring, repos.Group, repos.Member, repos.Product)
+	templateService := template.NewService(repos.Template, repos.Group, repos.Member, repos.Product, groupService)
+	suggestionService := suggestion.NewService(repos.Suggestion, repos.Group, repos.Member, repos.Product)
+	pantryService := pantry.NewService(repos.Pantry, repos.Group, repos.Member, repos.Product, groupService)
+	recipeService := recipe.NewService(repos.Recipe, repos.Group, repos.Member, repos.Product, repos.Pantry, groupService)
+	mealPlanService := mealplan.NewService(repos.MealPlan, repos.Group, repos.Member, repos.Recipe, repos.Product, repos.Pantry, groupService)
+	storeService := store.NewService(repos.Store, repos.Group, repos.Member, repos.Product)
+	barcodeService := barcode.NewService(repos.Barcode, repos.Group, repos.Member, repos.Product)
 
 	return &Services{
-		Auth:    authService,
-		User:    userService,
-		Group:   groupService,
-		Product: productService,
+		Auth:       authService,
+		User:       userService,
+		Group:      groupService,
+		Product:    productService,
+		Trip:       tripService,
+		Expense:    expenseService,
+		Stats:      statsService,
+		Price:      priceService,
+		Budget:     budgetService,
+		Currency:   currencyService,
+		Recurring:  recurringService,
+		Template:   templateService,
+		Suggestion: suggestionService,
+		Pantry:     pantryService,
+		Recipe:     recipeService,
+		MealPlan:   mealPlanService,
+		Store:      storeService,
+		Barcode:    barcodeService,
 	}
 }
@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// PeriodDTO selects the products bought in [From, To), an empty bound is open
+type PeriodDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+	From    *time.Time
+	To      *time.Time
+	Top     int
+}
+
+type CategoryTotalDTO struct {
+	CategoryID   uint64          `json:"category_id" db:"category_id"`
+	CategoryName string          `json:"category_name" db:"category_name"`
+	Items        int             `json:"items" db:"items"`
+	Total        decimal.Decimal `json:"total" db:"total"`
+}
+
+type MemberTotalDTO struct {
+	UserID   *uint64         `json:"user_id" db:"user_id"`
+	Username *string         `json:"username" db:"username"`
+	Items    int             `json:"items" db:"items"`
+	Total    decimal.Decimal `json:"total" db:"total"`
+}
+
+type MonthTotalDTO struct {
+	// Month is formatted as "2025-02"
+	Month string          `json:"month" db:"month"`
+	Items int             `json:"items" db:"items"`
+	Total decimal.Decimal `json:"total" db:"total"`
+}
+
+// AveragePriceDTO is computed over the priced products only, UnitPrice is the price of one unit
+type AveragePriceDTO struct {
+	ProductNameID uint64          `json:"product_name_id" db:"product_name_id"`
+	ProductName   string          `json:"product_name" db:"product_name"`
+	Unit          string          `json:"unit" db:"unit"`
+	Items         int             `json:"items" db:"items"`
+	Price         decimal.Decimal `json:"price" db:"price"`
+	UnitPrice     decimal.Decimal `json:"unit_price" db:"unit_price"`
+}
+
+type TopItemDTO struct {
+	ProductNameID uint64          `json:"product_name_id" db:"product_name_id"`
+	ProductName   string          `json:"product_name" db:"product_name"`
+	Items         int             `json:"items" db:"items"`
+	Total         decimal.Decimal `json:"total" db:"total"`
+}
+
+type TotalsDTO struct {
+	Items       int             `json:"items" db:"items"`
+	PricedItems int             `json:"priced_items" db:"priced_items"`
+	Total       decimal.Decimal `json:"total" db:"total"`
+}
+
+// StatsDTO is in the currency of the group, the prices in other currencies are converted
+type StatsDTO struct {
+	Currency      string             `json:"currency"`
+	Totals        TotalsDTO          `json:"totals"`
+	ByCategory    []CategoryTotalDTO `json:"by_category"`
+	ByMember      []MemberTotalDTO   `json:"by_member"`
+	ByMonth       []MonthTotalDTO    `json:"by_month"`
+	AveragePrices []AveragePriceDTO  `json:"average_prices"`
+	TopItems      []TopItemDTO       `json:"top_items"`
+}
+
+// ItemDTO is a bought product as it is exported
+type ItemDTO struct {
+	ProductID    uint64    `db:"product_id"`
+	BoughtAt     time.Time `db:"bought_at"`
+	ProductName  string    `db:"product_name"`
+	CategoryName string    `db:"category_name"`
+	Quantity     float64   `db:"quantity"`
+	Unit         string    `db:"unit"`
+	// Price is in Currency, GroupPrice is converted to the currency of the group
+	Price      *decimal.Decimal `db:"original_price"`
+	Currency   string           `db:"currency"`
+	GroupPrice *decimal.Decimal `db:"price"`
+	BoughtBy   *string          `db:"bought_by"`
+	TripID     *uint64          `db:"trip_id"`
+	Store      *string          `db:"store"`
+}
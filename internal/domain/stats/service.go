@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+)
+
+type Repository interface {
+	GetTotals(ctx context.Context, dto PeriodDTO) (TotalsDTO, error)
+	GetTotalsByCategory(ctx context.Context, dto PeriodDTO) ([]CategoryTotalDTO, error)
+	GetTotalsByMember(ctx context.Context, dto PeriodDTO) ([]MemberTotalDTO, error)
+	GetTotalsByMonth(ctx context.Context, dto PeriodDTO) ([]MonthTotalDTO, error)
+	GetAveragePrices(ctx context.Context, dto PeriodDTO) ([]AveragePriceDTO, error)
+	GetTopItems(ctx context.Context, dto PeriodDTO) ([]TopItemDTO, error)
+	GetItems(ctx context.Context, dto PeriodDTO) ([]ItemDTO, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type Service struct {
+	repo       Repository
+	groupRepo  GroupRepository
+	memberRepo MemberRepository
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository) *Service {
+	return &Service{
+		repo:       repo,
+		groupRepo:  groupRepo,
+		memberRepo: memberRepo,
+	}
+}
+
+// GetStats returns the spending of the group over the period, only bought products are counted
+func (s *Service) GetStats(ctx context.Context, dto PeriodDTO) (StatsDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return StatsDTO{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return StatsDTO{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	stats := StatsDTO{Currency: group.Currency}
+
+	stats.Totals, err = s.repo.GetTotals(ctx, dto)
+	if err != nil {
+		return StatsDTO{}, fmt.Errorf("failed to get totals: %w", err)
+	}
+
+	stats.ByCategory, err = s.repo.GetTotalsByCategory(ctx, dto)
+	if err != nil {
+		return StatsDTO{}, fmt.Errorf("failed to get totals by category: %w", err)
+	}
+
+	stats.ByMember, err = s.repo.GetTotalsByMember(ctx, dto)
+	if err != nil {
+		return StatsDTO{}, fmt.Errorf("failed to get totals by member: %w", err)
+	}
+
+	stats.ByMonth, err = s.repo.GetTotalsByMonth(ctx, dto)
+	if err != nil {
+		return StatsDTO{}, fmt.Errorf("failed to get totals by month: %w", err)
+	}
+
+	stats.AveragePrices, err = s.repo.GetAveragePrices(ctx, dto)
+	if err != nil {
+		return StatsDTO{}, fmt.Errorf("failed to get average prices: %w", err)
+	}
+
+	stats.TopItems, err = s.repo.GetTopItems(ctx, dto)
+	if err != nil {
+		return StatsDTO{}, fmt.Errorf("failed to get top items: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetItems returns the products the group bought over the period, oldest first
+func (s *Service) GetItems(ctx context.Context, dto PeriodDTO) ([]ItemDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	return s.repo.GetItems(ctx, dto)
+}
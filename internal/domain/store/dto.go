@@ -0,0 +1,59 @@
+package store
+
+import "time"
+
+type NewSectionDTO struct {
+	Name        string
+	CategoryIDs []uint64
+}
+
+// CreateStoreDTO lists the sections in the order they are walked through
+type CreateStoreDTO struct {
+	GroupID  uint64
+	UserID   uint64
+	Name     string
+	Sections []NewSectionDTO
+}
+
+// UpdateStoreDTO renames the store when Name is set and replaces its sections when Sections is not nil
+type UpdateStoreDTO struct {
+	GroupID  uint64
+	UserID   uint64
+	StoreID  uint64
+	Name     string
+	Sections []NewSectionDTO
+}
+
+type StoreUserDTO struct {
+	GroupID uint64
+	UserID  uint64
+	StoreID uint64
+}
+
+type GroupStoresDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+}
+
+type SectionCategoryDTO struct {
+	SectionID  uint64 `json:"-" db:"section_id"`
+	CategoryID uint64 `json:"category_id" db:"category_id"`
+	Name       string `json:"name" db:"name"`
+}
+
+type SectionDTO struct {
+	SectionID  uint64               `json:"section_id" db:"section_id"`
+	StoreID    uint64               `json:"-" db:"store_id"`
+	Name       string               `json:"name" db:"name"`
+	Position   int                  `json:"position" db:"position"`
+	Categories []SectionCategoryDTO `json:"categories" db:"-"`
+}
+
+type StoreDTO struct {
+	StoreID   uint64       `json:"store_id" db:"store_id"`
+	Name      string       `json:"name" db:"name"`
+	CreatedBy uint64       `json:"created_by" db:"created_by"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	Sections  []SectionDTO `json:"sections" db:"-"`
+}
@@ -0,0 +1,21 @@
+package store
+
+import "time"
+
+// Store of the group, its sections are walked through in the order of Position
+type Store struct {
+	StoreID   uint64
+	GroupID   uint64
+	Name      string
+	CreatedBy uint64
+	CreatedAt time.Time
+}
+
+// Section of a store holds the categories, a category is in one section of a store
+type Section struct {
+	SectionID   uint64
+	StoreID     uint64
+	Name        string
+	Position    int
+	CategoryIDs []uint64
+}
@@ -0,0 +1,245 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, store Store, sections []Section) (uint64, error)
+	GetById(ctx context.Context, storeID uint64) (Store, error)
+	Update(ctx context.Context, store Store, sections []Section) error
+	Delete(ctx context.Context, storeID uint64) error
+	GetStores(ctx context.Context, groupID uint64) ([]StoreDTO, error)
+	GetSections(ctx context.Context, storeIDs []uint64) ([]SectionDTO, error)
+	GetSectionCategories(ctx context.Context, sectionIDs []uint64, locales []string) ([]SectionCategoryDTO, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type ProductRepository interface {
+	GetCategoryById(ctx context.Context, categoryID uint64) (product.Category, error)
+}
+
+type Service struct {
+	repo        Repository
+	groupRepo   GroupRepository
+	memberRepo  MemberRepository
+	productRepo ProductRepository
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, productRepo ProductRepository) *Service {
+	return &Service{
+		repo:        repo,
+		groupRepo:   groupRepo,
+		memberRepo:  memberRepo,
+		productRepo: productRepo,
+	}
+}
+
+func (s *Service) CreateStore(ctx context.Context, dto CreateStoreDTO) (uint64, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	sections, err := s.newSections(ctx, dto.Sections)
+	if err != nil {
+		return 0, err
+	}
+
+	store := Store{
+		GroupID:   group.GroupID,
+		Name:      dto.Name,
+		CreatedBy: membr.UserID,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	return s.repo.Create(ctx, store, sections)
+}
+
+// GetStores returns the stores of the group with their sections in order
+func (s *Service) GetStores(ctx context.Context, dto GroupStoresDTO) ([]StoreDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	stores, err := s.repo.GetStores(ctx, group.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stores: %w", err)
+	}
+
+	storeIDs := make([]uint64, 0, len(stores))
+	for _, store := range stores {
+		storeIDs = append(storeIDs, store.StoreID)
+	}
+
+	sections, err := s.repo.GetSections(ctx, storeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+
+	sectionIDs := make([]uint64, 0, len(sections))
+	for _, section := range sections {
+		sectionIDs = append(sectionIDs, section.SectionID)
+	}
+
+	categories, err := s.repo.GetSectionCategories(ctx, sectionIDs, dto.Locales)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section categories: %w", err)
+	}
+
+	bySection := make(map[uint64][]SectionCategoryDTO, len(sections))
+	for _, category := range categories {
+		bySection[category.SectionID] = append(bySection[category.SectionID], category)
+	}
+
+	byStore := make(map[uint64][]SectionDTO, len(stores))
+	for _, section := range sections {
+		section.Categories = bySection[section.SectionID]
+		if section.Categories == nil {
+			section.Categories = []SectionCategoryDTO{}
+		}
+
+		byStore[section.StoreID] = append(byStore[section.StoreID], section)
+	}
+
+	for i := range stores {
+		stores[i].Sections = byStore[stores[i].StoreID]
+		if stores[i].Sections == nil {
+			stores[i].Sections = []SectionDTO{}
+		}
+	}
+
+	return stores, nil
+}
+
+func (s *Service) UpdateStore(ctx context.Context, dto UpdateStoreDTO) error {
+	store, err := s.getStore(ctx, StoreUserDTO{
+		GroupID: dto.GroupID,
+		UserID:  dto.UserID,
+		StoreID: dto.StoreID,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if dto.Name != "" {
+		store.Name = dto.Name
+	}
+
+	var sections []Section
+	if dto.Sections != nil {
+		sections, err = s.newSections(ctx, dto.Sections)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Update(ctx, store, sections)
+}
+
+func (s *Service) DeleteStore(ctx context.Context, dto StoreUserDTO) error {
+	store, err := s.getStore(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, store.StoreID)
+}
+
+// getStore returns the store of the group, if the user is a member of the group
+func (s *Service) getStore(ctx context.Context, dto StoreUserDTO) (Store, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Store{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Store{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	store, err := s.repo.GetById(ctx, dto.StoreID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Store{}, domainErr.ErrStoreNotFound
+		}
+
+		return Store{}, fmt.Errorf("failed to get store: %w", err)
+	}
+
+	if store.GroupID != group.GroupID {
+		return Store{}, domainErr.ErrStoreNotFound
+	}
+
+	return store, nil
+}
+
+// newSections numbers the sections in the given order and checks that every category exists and is in one section
+func (s *Service) newSections(ctx context.Context, dtos []NewSectionDTO) ([]Section, error) {
+	sections := make([]Section, 0, len(dtos))
+	seen := make(map[uint64]bool)
+
+	for i, dto := range dtos {
+		for _, categoryID := range dto.CategoryIDs {
+			if seen[categoryID] {
+				return nil, domainErr.ErrCategoryInSeveralSections
+			}
+			seen[categoryID] = true
+
+			if _, err := s.productRepo.GetCategoryById(ctx, categoryID); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return nil, domainErr.ErrCategoryNotFound
+				}
+
+				return nil, fmt.Errorf("failed to get category: %w", err)
+			}
+		}
+
+		sections = append(sections, Section{
+			Name:        dto.Name,
+			Position:    i + 1,
+			CategoryIDs: dto.CategoryIDs,
+		})
+	}
+
+	return sections, nil
+}
@@ -0,0 +1,38 @@
+package suggestion
+
+type GroupSuggestionsDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+	Limit   int
+}
+
+type DismissDTO struct {
+	GroupID       uint64
+	UserID        uint64
+	ProductNameID uint64
+}
+
+type ProductNameDTO struct {
+	ProductNameID uint64 `db:"product_name_id"`
+	Name          string `db:"name"`
+}
+
+// ReasonDTO explains a suggestion, IntervalDays and DaysSinceLast are set for the interval kind,
+// WithProductNameID and WithProductName are the product on the list for the together kind
+type ReasonDTO struct {
+	Kind              string  `json:"kind"`
+	Text              string  `json:"text"`
+	IntervalDays      *int    `json:"interval_days,omitempty"`
+	DaysSinceLast     *int    `json:"days_since_last,omitempty"`
+	WithProductNameID *uint64 `json:"with_product_name_id,omitempty"`
+	WithProductName   *string `json:"with_product_name,omitempty"`
+}
+
+// SuggestionDTO is a product name worth adding to the list, Score is between 0 and 1
+type SuggestionDTO struct {
+	ProductNameID uint64      `json:"product_name_id"`
+	ProductName   string      `json:"product_name"`
+	Score         float64     `json:"score"`
+	Reasons       []ReasonDTO `json:"reasons"`
+}
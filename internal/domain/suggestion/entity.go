@@ -0,0 +1,25 @@
+package suggestion
+
+import "time"
+
+const (
+	// KindInterval is a product the group buys regularly and is due again
+	KindInterval = "interval"
+
+	// KindTogether is a product the group usually buys with one already on the list
+	KindTogether = "together"
+)
+
+// Purchase is a day the group bought the product name
+type Purchase struct {
+	ProductNameID uint64
+	Day           time.Time
+}
+
+// Dismissal hides the suggestions of the product name until it is bought again
+type Dismissal struct {
+	GroupID       uint64
+	ProductNameID uint64
+	DismissedBy   uint64
+	DismissedAt   time.Time
+}
@@ -0,0 +1,311 @@
+package suggestion
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// historyDays is how far back the purchases of the group are analysed
+	historyDays = 365
+
+	// minPurchases is how many purchase days a product name needs to have a usual interval
+	minPurchases = 3
+
+	// dueRatio is the part of the usual interval after which the product is suggested again
+	dueRatio = 0.9
+
+	// minTogether is how many times two product names must be bought on the same day to make a pair
+	minTogether = 3
+
+	// minConfidence is the share of the purchases of the product on the list that include the pair
+	minConfidence = 0.5
+
+	// togetherWeight keeps the pairs below the products that are due
+	togetherWeight = 0.8
+)
+
+type Repository interface {
+	GetPurchases(ctx context.Context, groupID uint64, since time.Time) ([]Purchase, error)
+	GetOpenProductNameIds(ctx context.Context, groupID uint64) ([]uint64, error)
+	GetDismissals(ctx context.Context, groupID uint64) ([]Dismissal, error)
+	Dismiss(ctx context.Context, dismissal Dismissal) error
+	GetProductNames(ctx context.Context, productNameIDs []uint64, locales []string) ([]ProductNameDTO, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type ProductRepository interface {
+	GetByProductNameId(ctx context.Context, productNameID uint64) (product.ProductName, error)
+}
+
+type Service struct {
+	repo        Repository
+	groupRepo   GroupRepository
+	memberRepo  MemberRepository
+	productRepo ProductRepository
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, productRepo ProductRepository) *Service {
+	return &Service{
+		repo:        repo,
+		groupRepo:   groupRepo,
+		memberRepo:  memberRepo,
+		productRepo: productRepo,
+	}
+}
+
+// GetSuggestions ranks the product names the group buys regularly and is due to buy again and the ones
+// usually bought together with the products on the list. Products on the list and dismissed ones are left out
+func (s *Service) GetSuggestions(ctx context.Context, dto GroupSuggestionsDTO) ([]SuggestionDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	purchases, err := s.repo.GetPurchases(ctx, group.GroupID, today.AddDate(0, 0, -historyDays))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchases: %w", err)
+	}
+
+	openIDs, err := s.repo.GetOpenProductNameIds(ctx, group.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open products: %w", err)
+	}
+
+	dismissals, err := s.repo.GetDismissals(ctx, group.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dismissals: %w", err)
+	}
+
+	days := make(map[uint64][]time.Time)
+	for _, purchase := range purchases {
+		days[purchase.ProductNameID] = append(days[purchase.ProductNameID], purchase.Day)
+	}
+
+	productNameIDs := make([]uint64, 0, len(days))
+	for productNameID := range days {
+		productNameIDs = append(productNameIDs, productNameID)
+	}
+
+	productNames, err := s.repo.GetProductNames(ctx, productNameIDs, dto.Locales)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product names: %w", err)
+	}
+
+	names := make(map[uint64]string, len(productNames))
+	for _, productName := range productNames {
+		names[productName.ProductNameID] = productName.Name
+	}
+
+	hidden := make(map[uint64]bool, len(openIDs)+len(dismissals))
+	for _, productNameID := range openIDs {
+		hidden[productNameID] = true
+	}
+
+	for _, dismissal := range dismissals {
+		productDays := days[dismissal.ProductNameID]
+		if len(productDays) == 0 || productDays[len(productDays)-1].Before(dismissal.DismissedAt) {
+			hidden[dismissal.ProductNameID] = true
+		}
+	}
+
+	suggestions := make(map[uint64]*SuggestionDTO)
+	suggest := func(productNameID uint64, score float64, reason ReasonDTO) {
+		suggestion, ok := suggestions[productNameID]
+		if !ok {
+			suggestion = &SuggestionDTO{ProductNameID: productNameID, ProductName: names[productNameID]}
+			suggestions[productNameID] = suggestion
+		}
+
+		suggestion.Score = math.Max(suggestion.Score, math.Round(score*100)/100)
+		suggestion.Reasons = append(suggestion.Reasons, reason)
+	}
+
+	for productNameID, productDays := range days {
+		if hidden[productNameID] {
+			continue
+		}
+
+		interval, ok := usualInterval(productDays)
+		if !ok {
+			continue
+		}
+
+		since := int(today.Sub(productDays[len(productDays)-1]).Hours() / 24)
+		if float64(since) < dueRatio*float64(interval) {
+			continue
+		}
+
+		suggest(productNameID, math.Min(1, float64(since)/float64(interval)/2), ReasonDTO{
+			Kind:          KindInterval,
+			Text:          fmt.Sprintf("usually bought every %d days, last time %d days ago", interval, since),
+			IntervalDays:  &interval,
+			DaysSinceLast: &since,
+		})
+	}
+
+	pairs := togetherCounts(days)
+
+	for _, openID := range openIDs {
+		total := len(days[openID])
+		if total == 0 {
+			continue
+		}
+
+		for _, pair := range pairs[openID] {
+			confidence := float64(pair.count) / float64(total)
+			if hidden[pair.productNameID] || pair.count < minTogether || confidence < minConfidence {
+				continue
+			}
+
+			withID, withName := openID, names[openID]
+
+			suggest(pair.productNameID, togetherWeight*confidence, ReasonDTO{
+				Kind:              KindTogether,
+				Text:              fmt.Sprintf("bought together with %s in %d of %d purchases", withName, pair.count, total),
+				WithProductNameID: &withID,
+				WithProductName:   &withName,
+			})
+		}
+	}
+
+	result := make([]SuggestionDTO, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		result = append(result, *suggestion)
+	}
+
+	slices.SortFunc(result, func(a, b SuggestionDTO) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.ProductNameID, b.ProductNameID))
+	})
+
+	if len(result) > dto.Limit {
+		result = result[:dto.Limit]
+	}
+
+	return result, nil
+}
+
+// Dismiss stops suggesting the product name to the group until it is bought again
+func (s *Service) Dismiss(ctx context.Context, dto DismissDTO) error {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrMemberNotFound
+		}
+	}
+
+	productName, err := s.productRepo.GetByProductNameId(ctx, dto.ProductNameID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domainErr.ErrProductNotFound
+		}
+
+		return fmt.Errorf("failed to get product name: %w", err)
+	}
+
+	if productName.GroupID != nil && *productName.GroupID != group.GroupID {
+		return domainErr.ErrProductNotFound
+	}
+
+	return s.repo.Dismiss(ctx, Dismissal{
+		GroupID:       group.GroupID,
+		ProductNameID: productName.ProductNameID,
+		DismissedBy:   membr.UserID,
+		DismissedAt:   time.Now().UTC(),
+	})
+}
+
+// usualInterval returns the median number of days between the sorted purchase days
+func usualInterval(days []time.Time) (int, bool) {
+	if len(days) < minPurchases {
+		return 0, false
+	}
+
+	gaps := make([]float64, 0, len(days)-1)
+	for i := 1; i < len(days); i++ {
+		gaps = append(gaps, days[i].Sub(days[i-1]).Hours()/24)
+	}
+
+	slices.Sort(gaps)
+
+	median := gaps[len(gaps)/2]
+	if len(gaps)%2 == 0 {
+		median = (gaps[len(gaps)/2-1] + gaps[len(gaps)/2]) / 2
+	}
+
+	interval := int(math.Round(median))
+	if interval < 1 {
+		return 0, false
+	}
+
+	return interval, true
+}
+
+type pair struct {
+	productNameID uint64
+	count         int
+}
+
+// togetherCounts returns for every product name how many days each other product name was bought with it
+func togetherCounts(days map[uint64][]time.Time) map[uint64][]pair {
+	baskets := make(map[time.Time][]uint64)
+	for productNameID, productDays := range days {
+		for _, day := range productDays {
+			baskets[day] = append(baskets[day], productNameID)
+		}
+	}
+
+	counts := make(map[[2]uint64]int)
+	for _, basket := range baskets {
+		for i := range basket {
+			for j := range basket {
+				if i != j {
+					counts[[2]uint64{basket[i], basket[j]}]++
+				}
+			}
+		}
+	}
+
+	pairs := make(map[uint64][]pair)
+	for key, count := range counts {
+		pairs[key[0]] = append(pairs[key[0]], pair{productNameID: key[1], count: count})
+	}
+
+	return pairs
+}
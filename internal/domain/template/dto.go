@@ -0,0 +1,72 @@
+package template
+
+import (
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"time"
+)
+
+type NewItemDTO struct {
+	ProductNameID uint64
+	Quantity      float64
+	Unit          string
+	Note          string
+}
+
+type CreateTemplateDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Name    string
+	// Personal makes the template belong to the user instead of the group
+	Personal bool
+	// FromOpenProducts copies the open products of the group, Items are ignored then
+	FromOpenProducts bool
+	Items            []NewItemDTO
+}
+
+// UpdateTemplateDTO renames the template when Name is set and replaces its items when Items is not nil
+type UpdateTemplateDTO struct {
+	GroupID    uint64
+	UserID     uint64
+	TemplateID uint64
+	Name       string
+	Items      []NewItemDTO
+}
+
+type TemplateUserDTO struct {
+	GroupID    uint64
+	UserID     uint64
+	TemplateID uint64
+}
+
+type GroupTemplatesDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+}
+
+type TemplateItemDTO struct {
+	TemplateItemID uint64  `json:"template_item_id" db:"template_item_id"`
+	TemplateID     uint64  `json:"-" db:"template_id"`
+	ProductNameID  uint64  `json:"product_name_id" db:"product_name_id"`
+	ProductName    string  `json:"product_name" db:"product_name"`
+	Quantity       float64 `json:"quantity" db:"quantity"`
+	Unit           string  `json:"unit" db:"unit"`
+	Note           string  `json:"note" db:"note"`
+}
+
+// TemplateDTO is a template of the group or a personal template of the user
+type TemplateDTO struct {
+	TemplateID uint64            `json:"template_id" db:"template_id"`
+	Name       string            `json:"name" db:"name"`
+	Personal   bool              `json:"personal" db:"personal"`
+	CreatedBy  uint64            `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+	Items      []TemplateItemDTO `json:"items" db:"-"`
+}
+
+// AppliedTemplateDTO lists the products added or merged by applying a template, Skipped are the
+// product names of a personal template that are private to another group
+type AppliedTemplateDTO struct {
+	Products []group.AddedProductDTO
+	Skipped  []uint64
+}
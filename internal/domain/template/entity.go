@@ -0,0 +1,23 @@
+package template
+
+import "time"
+
+// Template is a reusable set of product names, it belongs either to a group (GroupID)
+// or to one user (UserID), who can apply it in any of their groups
+type Template struct {
+	TemplateID uint64
+	Name       string
+	GroupID    *uint64
+	UserID     *uint64
+	CreatedBy  uint64
+	CreatedAt  time.Time
+}
+
+type Item struct {
+	TemplateItemID uint64
+	TemplateID     uint64
+	ProductNameID  uint64
+	Quantity       float64
+	Unit           string
+	Note           string
+}
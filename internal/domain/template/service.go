@@ -0,0 +1,331 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, template Template, items []Item) (uint64, error)
+	GetById(ctx context.Context, templateID uint64) (Template, error)
+	GetItems(ctx context.Context, templateID uint64) ([]Item, error)
+	Update(ctx context.Context, template Template, items []Item) error
+	Delete(ctx context.Context, templateID uint64) error
+	GetTemplates(ctx context.Context, groupID uint64, userID uint64) ([]TemplateDTO, error)
+	GetTemplateItems(ctx context.Context, templateIDs []uint64, locales []string) ([]TemplateItemDTO, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type ProductRepository interface {
+	GetByProductNameId(ctx context.Context, productNameID uint64) (product.ProductName, error)
+	GetOpenProductsByGroupId(ctx context.Context, groupID uint64) ([]product.Product, error)
+}
+
+type GroupService interface {
+	AddProduct(ctx context.Context, dto group.CreateProductDTO) (group.AddedProductDTO, error)
+}
+
+type Service struct {
+	repo         Repository
+	groupRepo    GroupRepository
+	memberRepo   MemberRepository
+	productRepo  ProductRepository
+	groupService GroupService
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, productRepo ProductRepository, groupService GroupService) *Service {
+	return &Service{
+		repo:         repo,
+		groupRepo:    groupRepo,
+		memberRepo:   memberRepo,
+		productRepo:  productRepo,
+		groupService: groupService,
+	}
+}
+
+// CreateTemplate saves a template of the group or a personal one of the user, from the given
+// items or from the open products of the group
+func (s *Service) CreateTemplate(ctx context.Context, dto CreateTemplateDTO) (uint64, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	var items []Item
+	if dto.FromOpenProducts {
+		items, err = s.openProductItems(ctx, group.GroupID)
+	} else {
+		items, err = s.newItems(ctx, group.GroupID, dto.Items)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(items) == 0 {
+		return 0, domainErr.ErrTemplateEmpty
+	}
+
+	template := Template{
+		Name:      dto.Name,
+		CreatedBy: membr.UserID,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if dto.Personal {
+		template.UserID = &membr.UserID
+	} else {
+		template.GroupID = &group.GroupID
+	}
+
+	return s.repo.Create(ctx, template, items)
+}
+
+// GetTemplates returns the templates of the group and the personal templates of the user with their items
+func (s *Service) GetTemplates(ctx context.Context, dto GroupTemplatesDTO) ([]TemplateDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	templates, err := s.repo.GetTemplates(ctx, group.GroupID, membr.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+
+	templateIDs := make([]uint64, 0, len(templates))
+	for _, template := range templates {
+		templateIDs = append(templateIDs, template.TemplateID)
+	}
+
+	items, err := s.repo.GetTemplateItems(ctx, templateIDs, dto.Locales)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template items: %w", err)
+	}
+
+	byTemplate := make(map[uint64][]TemplateItemDTO, len(templates))
+	for _, item := range items {
+		byTemplate[item.TemplateID] = append(byTemplate[item.TemplateID], item)
+	}
+
+	for i := range templates {
+		templates[i].Items = byTemplate[templates[i].TemplateID]
+		if templates[i].Items == nil {
+			templates[i].Items = []TemplateItemDTO{}
+		}
+	}
+
+	return templates, nil
+}
+
+func (s *Service) UpdateTemplate(ctx context.Context, dto UpdateTemplateDTO) error {
+	template, err := s.getTemplate(ctx, TemplateUserDTO{
+		GroupID:    dto.GroupID,
+		UserID:     dto.UserID,
+		TemplateID: dto.TemplateID,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if dto.Name != "" {
+		template.Name = dto.Name
+	}
+
+	var items []Item
+	if dto.Items != nil {
+		items, err = s.newItems(ctx, dto.GroupID, dto.Items)
+		if err != nil {
+			return err
+		}
+
+		if len(items) == 0 {
+			return domainErr.ErrTemplateEmpty
+		}
+	}
+
+	return s.repo.Update(ctx, template, items)
+}
+
+func (s *Service) DeleteTemplate(ctx context.Context, dto TemplateUserDTO) error {
+	template, err := s.getTemplate(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, template.TemplateID)
+}
+
+// ApplyTemplate adds every item of the template to the group like group.Service.AddProduct does,
+// always merging with the open products. The items are added one by one, so an error can leave
+// the template applied partly
+func (s *Service) ApplyTemplate(ctx context.Context, dto TemplateUserDTO) (AppliedTemplateDTO, error) {
+	template, err := s.getTemplate(ctx, dto)
+	if err != nil {
+		return AppliedTemplateDTO{}, err
+	}
+
+	items, err := s.repo.GetItems(ctx, template.TemplateID)
+	if err != nil {
+		return AppliedTemplateDTO{}, fmt.Errorf("failed to get template items: %w", err)
+	}
+
+	merge := true
+	applied := AppliedTemplateDTO{
+		Products: make([]group.AddedProductDTO, 0, len(items)),
+		Skipped:  make([]uint64, 0),
+	}
+
+	for _, item := range items {
+		added, err := s.groupService.AddProduct(ctx, group.CreateProductDTO{
+			UserID:        dto.UserID,
+			GroupID:       dto.GroupID,
+			ProductNameID: item.ProductNameID,
+			Quantity:      item.Quantity,
+			Unit:          item.Unit,
+			Note:          item.Note,
+			Merge:         &merge,
+		})
+
+		if err != nil {
+			// a personal template can hold product names private to another group of the user
+			if errors.Is(err, domainErr.ErrProductNotFound) {
+				applied.Skipped = append(applied.Skipped, item.ProductNameID)
+				continue
+			}
+
+			return applied, err
+		}
+
+		applied.Products = append(applied.Products, added)
+	}
+
+	return applied, nil
+}
+
+// getTemplate returns the template of the group or the personal template of the user, if the user is a member of the group
+func (s *Service) getTemplate(ctx context.Context, dto TemplateUserDTO) (Template, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Template{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Template{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	template, err := s.repo.GetById(ctx, dto.TemplateID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Template{}, domainErr.ErrTemplateNotFound
+		}
+
+		return Template{}, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	ofGroup := template.GroupID != nil && *template.GroupID == group.GroupID
+	ofUser := template.UserID != nil && *template.UserID == membr.UserID
+
+	if !ofGroup && !ofUser {
+		return Template{}, domainErr.ErrTemplateNotFound
+	}
+
+	return template, nil
+}
+
+// newItems checks that the product names are visible to the group and fills in the default units
+func (s *Service) newItems(ctx context.Context, groupID uint64, dtos []NewItemDTO) ([]Item, error) {
+	items := make([]Item, 0, len(dtos))
+
+	for _, dto := range dtos {
+		productName, err := s.productRepo.GetByProductNameId(ctx, dto.ProductNameID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, domainErr.ErrProductNotFound
+			}
+
+			return nil, fmt.Errorf("failed to get product name: %w", err)
+		}
+
+		if productName.GroupID != nil && *productName.GroupID != groupID {
+			return nil, domainErr.ErrProductNotFound
+		}
+
+		item := Item{
+			ProductNameID: productName.ProductNameID,
+			Quantity:      dto.Quantity,
+			Unit:          dto.Unit,
+			Note:          dto.Note,
+		}
+
+		if item.Unit == "" {
+			item.Unit = productName.DefaultUnit
+		}
+
+		if !unit.IsValid(item.Unit) {
+			return nil, domainErr.ErrInvalidUnit
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *Service) openProductItems(ctx context.Context, groupID uint64) ([]Item, error) {
+	products, err := s.productRepo.GetOpenProductsByGroupId(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open products: %w", err)
+	}
+
+	items := make([]Item, 0, len(products))
+	for _, product := range products {
+		items = append(items, Item{
+			ProductNameID: product.ProductNameID,
+			Quantity:      product.Quantity,
+			Unit:          product.Unit,
+			Note:          product.Note,
+		})
+	}
+
+	return items, nil
+}
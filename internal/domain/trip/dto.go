@@ -0,0 +1,55 @@
+package trip
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type CreateTripDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Store   string
+	Date    time.Time
+	// Total is the sum of the item prices when nil
+	Total *decimal.Decimal
+	// Currency is the currency of the group when empty
+	Currency string
+	Items    []Item
+}
+
+type GroupTripsDTO struct {
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+	Limit   int
+	Offset  int
+}
+
+type TripUserDTO struct {
+	TripID  uint64
+	GroupID uint64
+	UserID  uint64
+	Locales []string
+}
+
+type TripDTO struct {
+	TripID    uint64          `json:"trip_id" db:"trip_id"`
+	Store     string          `json:"store" db:"store"`
+	Date      time.Time       `json:"date" db:"trip_date"`
+	Total     decimal.Decimal `json:"total" db:"total"`
+	Currency  string          `json:"currency" db:"currency"`
+	BuyerID   uint64          `json:"buyer_id" db:"buyer_id"`
+	Buyer     string          `json:"buyer" db:"buyer"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	Items     []TripItemDTO   `json:"items" db:"-"`
+}
+
+type TripItemDTO struct {
+	TripID      uint64           `json:"-" db:"trip_id"`
+	ProductID   uint64           `json:"product_id" db:"product_id"`
+	ProductName string           `json:"product_name" db:"product_name"`
+	Quantity    float64          `json:"quantity" db:"quantity"`
+	Unit        string           `json:"unit" db:"unit"`
+	Price       *decimal.Decimal `json:"price" db:"price"`
+	Currency    string           `json:"currency" db:"currency"`
+}
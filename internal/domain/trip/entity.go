@@ -0,0 +1,24 @@
+package trip
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type Trip struct {
+	TripID    uint64
+	GroupID   uint64
+	BuyerID   uint64
+	Store     string
+	Date      time.Time
+	Total     decimal.Decimal
+	CreatedAt time.Time
+	// Currency of the total and the item prices, the currency of the group when nil
+	Currency *string
+}
+
+// Item is a product bought during the trip with the price paid for it
+type Item struct {
+	ProductID uint64
+	Price     *decimal.Decimal
+}
@@ -0,0 +1,207 @@
+package trip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/tclutin/shoppinglist-api/internal/domain/currency"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/member"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, trip Trip, items []Item) (uint64, error)
+	GetGroupTrip(ctx context.Context, groupID uint64, tripID uint64) (TripDTO, error)
+	GetTripsByGroupId(ctx context.Context, groupID uint64, limit int, offset int) ([]TripDTO, error)
+	GetTripItems(ctx context.Context, tripIDs []uint64, locales []string) ([]TripItemDTO, error)
+}
+
+type GroupRepository interface {
+	GetById(ctx context.Context, groupID uint64) (group.Group, error)
+}
+
+type CurrencyRepository interface {
+	GetRate(ctx context.Context, code string) (currency.Rate, error)
+}
+
+type MemberRepository interface {
+	GetByUserAndGroupId(ctx context.Context, userID uint64, groupID uint64) (member.Member, error)
+}
+
+type BudgetService interface {
+	CheckThresholds(ctx context.Context, groupID uint64) error
+}
+
+type Service struct {
+	repo          Repository
+	groupRepo     GroupRepository
+	memberRepo    MemberRepository
+	currencyRepo  CurrencyRepository
+	budgetService BudgetService
+}
+
+func NewService(repo Repository, groupRepo GroupRepository, memberRepo MemberRepository, currencyRepo CurrencyRepository, budgetService BudgetService) *Service {
+	return &Service{
+		repo:          repo,
+		groupRepo:     groupRepo,
+		memberRepo:    memberRepo,
+		currencyRepo:  currencyRepo,
+		budgetService: budgetService,
+	}
+}
+
+// CreateTrip closes the open products of the group bought by the user in one go
+func (s *Service) CreateTrip(ctx context.Context, dto CreateTripDTO) (uint64, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrGroupNotFound
+		}
+	}
+
+	membr, err := s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrMemberNotFound
+		}
+	}
+
+	total := decimal.Zero
+	if dto.Total != nil {
+		total = *dto.Total
+	} else {
+		for _, item := range dto.Items {
+			if item.Price != nil {
+				total = total.Add(*item.Price)
+			}
+		}
+	}
+
+	trip := Trip{
+		GroupID:   group.GroupID,
+		BuyerID:   membr.UserID,
+		Store:     dto.Store,
+		Date:      dto.Date,
+		Total:     total,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if dto.Currency != "" {
+		_, err = s.currencyRepo.GetRate(ctx, dto.Currency)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, domainErr.ErrUnknownCurrency
+			}
+
+			return 0, fmt.Errorf("failed to get exchange rate: %w", err)
+		}
+
+		trip.Currency = &dto.Currency
+	}
+
+	tripID, err := s.repo.Create(ctx, trip, dto.Items)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domainErr.ErrProductUnavailable
+		}
+
+		return 0, fmt.Errorf("failed to create trip: %w", err)
+	}
+
+	// the trip is saved already, so a failed check is told apart from a failed checkout
+	if err = s.budgetService.CheckThresholds(ctx, group.GroupID); err != nil {
+		return tripID, fmt.Errorf("%w: %w", domainErr.ErrThresholdsNotChecked, err)
+	}
+
+	return tripID, nil
+}
+
+func (s *Service) GetGroupTrips(ctx context.Context, dto GroupTripsDTO) ([]TripDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domainErr.ErrMemberNotFound
+		}
+	}
+
+	trips, err := s.repo.GetTripsByGroupId(ctx, group.GroupID, dto.Limit, dto.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withItems(ctx, trips, dto.Locales)
+}
+
+func (s *Service) GetTrip(ctx context.Context, dto TripUserDTO) (TripDTO, error) {
+	group, err := s.groupRepo.GetById(ctx, dto.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TripDTO{}, domainErr.ErrGroupNotFound
+		}
+	}
+
+	_, err = s.memberRepo.GetByUserAndGroupId(ctx, dto.UserID, group.GroupID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TripDTO{}, domainErr.ErrMemberNotFound
+		}
+	}
+
+	trip, err := s.repo.GetGroupTrip(ctx, group.GroupID, dto.TripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TripDTO{}, domainErr.ErrTripNotFound
+		}
+
+		return TripDTO{}, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	trips, err := s.withItems(ctx, []TripDTO{trip}, dto.Locales)
+	if err != nil {
+		return TripDTO{}, err
+	}
+
+	return trips[0], nil
+}
+
+// withItems fills the items of the trips with one query
+func (s *Service) withItems(ctx context.Context, trips []TripDTO, locales []string) ([]TripDTO, error) {
+	if len(trips) == 0 {
+		return trips, nil
+	}
+
+	tripIDs := make([]uint64, 0, len(trips))
+	for _, trip := range trips {
+		tripIDs = append(tripIDs, trip.TripID)
+	}
+
+	items, err := s.repo.GetTripItems(ctx, tripIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	byTrip := make(map[uint64][]TripItemDTO, len(trips))
+	for _, item := range items {
+		byTrip[item.TripID] = append(byTrip[item.TripID], item)
+	}
+
+	for i := range trips {
+		trips[i].Items = byTrip[trips[i].TripID]
+		if trips[i].Items == nil {
+			trips[i].Items = []TripItemDTO{}
+		}
+	}
+
+	return trips, nil
+}
@@ -8,4 +8,6 @@ type User struct {
 	Password  string
 	Gender    string
 	CreatedAt time.Time
+	IsAdmin   bool
+	Locale    string
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
 	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/pkg/locale"
 )
 
 type Repository interface {
@@ -14,6 +15,7 @@ type Repository interface {
 	GetById(ctx context.Context, userID uint64) (User, error)
 	GetByUsername(ctx context.Context, username string) (User, error)
 	GetGroupsByUserId(ctx context.Context, userId uint64) ([]group.GroupDTO, error)
+	UpdateLocale(ctx context.Context, userID uint64, locale string) error
 }
 
 type Service struct {
@@ -64,3 +66,17 @@ func (s *Service) GetByUsername(ctx context.Context, username string) (User, err
 
 	return user, nil
 }
+
+func (s *Service) UpdateLocale(ctx context.Context, userID uint64, tag string) error {
+	user, err := s.GetById(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	userLocale := locale.Normalize(tag)
+	if !locale.IsValid(userLocale) {
+		return domainErr.ErrInvalidLocale
+	}
+
+	return s.repo.UpdateLocale(ctx, user.UserID, userLocale)
+}
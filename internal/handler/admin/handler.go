@@ -0,0 +1,560 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Service interface {
+	CreateCategory(ctx context.Context, dto product.CreateCategoryDTO) (uint64, error)
+	UpdateCategory(ctx context.Context, dto product.UpdateCategoryDTO) error
+	DeleteCategory(ctx context.Context, categoryID uint64) error
+	MergeCategories(ctx context.Context, dto product.MergeDTO) error
+
+	CreateGlobalProductName(ctx context.Context, dto product.CreateProductNameDTO) (uint64, error)
+	UpdateProductName(ctx context.Context, dto product.UpdateProductNameDTO) error
+	DeleteProductName(ctx context.Context, productNameID uint64) error
+	MergeProductNames(ctx context.Context, dto product.MergeDTO) error
+
+	ImportTranslations(ctx context.Context, translations product.Translations) (int, error)
+}
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewAdminHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "admin_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	adminRouter := router.Group("admin", mw.AuthMiddleware(authService), mw.AdminMiddleware(authService))
+	{
+		adminRouter.POST("/categories", h.CreateCategory)
+		adminRouter.PATCH("/categories/:category_id", h.UpdateCategory)
+		adminRouter.DELETE("/categories/:category_id", h.DeleteCategory)
+		adminRouter.POST("/categories/:category_id/merge", h.MergeCategories)
+
+		adminRouter.POST("/product-names", h.CreateProductName)
+		adminRouter.PATCH("/product-names/:product_name_id", h.UpdateProductName)
+		adminRouter.DELETE("/product-names/:product_name_id", h.DeleteProductName)
+		adminRouter.POST("/product-names/:product_name_id/merge", h.MergeProductNames)
+
+		adminRouter.POST("/translations", h.ImportTranslations)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		CreateCategory
+// @Description	create new category
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			input	body		CategoryRequest	true	"create new category"
+// @Success		200		{object}	CategoryResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/categories [post]
+func (h *Handler) CreateCategory(c *gin.Context) {
+	var request CategoryRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	categoryID, err := h.service.CreateCategory(c.Request.Context(), product.CreateCategoryDTO{
+		Name: request.Name,
+	})
+
+	if err != nil {
+		h.logger.Error("error occurred while processing CreateCategory", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, CategoryResponse{CategoryID: categoryID})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateCategory
+// @Description	rename a category
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			category_id	path		string	true	"category ID"
+// @Param			input	body		CategoryRequest	true	"rename a category"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/categories/{category_id} [PATCH]
+func (h *Handler) UpdateCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("category_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':category_id' is not correct", nil))
+		return
+	}
+
+	var request CategoryRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.UpdateCategory(c.Request.Context(), product.UpdateCategoryDTO{
+		CategoryID: categoryID,
+		Name:       request.Name,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrCategoryNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing UpdateCategory", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		DeleteCategory
+// @Description	delete an empty category
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			category_id	path		string	true	"category ID"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/categories/{category_id} [delete]
+func (h *Handler) DeleteCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("category_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':category_id' is not correct", nil))
+		return
+	}
+
+	err = h.service.DeleteCategory(c.Request.Context(), categoryID)
+	if err != nil {
+		if errors.Is(err, domainErr.ErrCategoryNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrCategoryInUse) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing DeleteCategory", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		MergeCategories
+// @Description	move all product names of a category to the target category and delete it
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			category_id	path		string	true	"category ID"
+// @Param			input	body		MergeRequest	true	"target category"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		400		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/categories/{category_id}/merge [post]
+func (h *Handler) MergeCategories(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("category_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':category_id' is not correct", nil))
+		return
+	}
+
+	var request MergeRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.MergeCategories(c.Request.Context(), product.MergeDTO{
+		SourceID: categoryID,
+		TargetID: request.TargetID,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrCategoryNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrCannotMergeIntoItself) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing MergeCategories", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		CreateProductName
+// @Description	create new global product name
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			input	body		CreateProductNameRequest	true	"create new product name"
+// @Success		200		{object}	ProductNameResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/product-names [post]
+func (h *Handler) CreateProductName(c *gin.Context) {
+	var request CreateProductNameRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	productNameID, err := h.service.CreateGlobalProductName(c.Request.Context(), product.CreateProductNameDTO{
+		CategoryID:  request.CategoryID,
+		Name:        request.Name,
+		DefaultUnit: request.DefaultUnit,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrCategoryNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNameAlreadyExists) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing CreateProductName", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, ProductNameResponse{ProductNameID: productNameID})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateProductName
+// @Description	rename a product name or move it to another category
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			product_name_id	path		string	true	"product name ID"
+// @Param			input	body		UpdateProductNameRequest	true	"update a product name"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/product-names/{product_name_id} [PATCH]
+func (h *Handler) UpdateProductName(c *gin.Context) {
+	productNameID, err := strconv.ParseUint(c.Param("product_name_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':product_name_id' is not correct", nil))
+		return
+	}
+
+	var request UpdateProductNameRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.UpdateProductName(c.Request.Context(), product.UpdateProductNameDTO{
+		ProductNameID: productNameID,
+		CategoryID:    request.CategoryID,
+		Name:          request.Name,
+		DefaultUnit:   request.DefaultUnit,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrCategoryNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNameAlreadyExists) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing UpdateProductName", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		DeleteProductName
+// @Description	delete a product name which isn't used by products
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			product_name_id	path		string	true	"product name ID"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/product-names/{product_name_id} [delete]
+func (h *Handler) DeleteProductName(c *gin.Context) {
+	productNameID, err := strconv.ParseUint(c.Param("product_name_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':product_name_id' is not correct", nil))
+		return
+	}
+
+	err = h.service.DeleteProductName(c.Request.Context(), productNameID)
+	if err != nil {
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNameInUse) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing DeleteProductName", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		MergeProductNames
+// @Description	re-point all products of a product name to the target product name and delete it
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			product_name_id	path		string	true	"product name ID"
+// @Param			input	body		MergeRequest	true	"target product name"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		400		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/product-names/{product_name_id}/merge [post]
+func (h *Handler) MergeProductNames(c *gin.Context) {
+	productNameID, err := strconv.ParseUint(c.Param("product_name_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':product_name_id' is not correct", nil))
+		return
+	}
+
+	var request MergeRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.MergeProductNames(c.Request.Context(), product.MergeDTO{
+		SourceID: productNameID,
+		TargetID: request.TargetID,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrCannotMergeIntoItself) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrForeignProductName) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing MergeProductNames", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		ImportTranslations
+// @Description	add or replace names of categories and product names for a locale
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			input	body		ImportTranslationsRequest	true	"names of the locale"
+// @Success		200		{object}	ImportTranslationsResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		400		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/translations [post]
+func (h *Handler) ImportTranslations(c *gin.Context) {
+	var request ImportTranslationsRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	translations := product.Translations{Locale: request.Locale}
+
+	for _, category := range request.Categories {
+		translations.Categories = append(translations.Categories, product.Translation{
+			ID:   category.ID,
+			Name: category.Name,
+		})
+	}
+
+	for _, productName := range request.ProductNames {
+		translations.ProductNames = append(translations.ProductNames, product.Translation{
+			ID:   productName.ID,
+			Name: productName.Name,
+		})
+	}
+
+	imported, err := h.service.ImportTranslations(c.Request.Context(), translations)
+	if err != nil {
+		if errors.Is(err, domainErr.ErrInvalidLocale) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing ImportTranslations", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, ImportTranslationsResponse{Imported: imported})
+}
@@ -0,0 +1,32 @@
+package admin
+
+type CategoryRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+type CreateProductNameRequest struct {
+	CategoryID  uint64 `json:"category_id" binding:"required"`
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	DefaultUnit string `json:"default_unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
+}
+
+type UpdateProductNameRequest struct {
+	CategoryID  uint64 `json:"category_id"`
+	Name        string `json:"name" binding:"omitempty,max=100"`
+	DefaultUnit string `json:"default_unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
+}
+
+type MergeRequest struct {
+	TargetID uint64 `json:"target_id" binding:"required"`
+}
+
+type TranslationRequest struct {
+	ID   uint64 `json:"id" binding:"required"`
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+type ImportTranslationsRequest struct {
+	Locale       string               `json:"locale" binding:"required,min=2,max=10"`
+	Categories   []TranslationRequest `json:"categories" binding:"dive"`
+	ProductNames []TranslationRequest `json:"product_names" binding:"dive"`
+}
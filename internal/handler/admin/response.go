@@ -0,0 +1,13 @@
+package admin
+
+type CategoryResponse struct {
+	CategoryID uint64 `json:"category_id"`
+}
+
+type ProductNameResponse struct {
+	ProductNameID uint64 `json:"product_name_id"`
+}
+
+type ImportTranslationsResponse struct {
+	Imported int `json:"imported"`
+}
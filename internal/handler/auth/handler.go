@@ -245,5 +245,7 @@ func (h *Handler) Who(c *gin.Context) {
 		Username:  usr.Username,
 		Gender:    usr.Gender,
 		CreatedAt: usr.CreatedAt,
+		IsAdmin:   usr.IsAdmin,
+		Locale:    usr.Locale,
 	})
 }
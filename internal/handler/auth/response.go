@@ -12,4 +12,6 @@ type CurrentUserResponse struct {
 	Username  string    `json:"username"`
 	Gender    string    `json:"gender"`
 	CreatedAt time.Time `json:"created_at"`
+	IsAdmin   bool      `json:"is_admin"`
+	Locale    string    `json:"locale"`
 }
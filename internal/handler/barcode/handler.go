@@ -0,0 +1,295 @@
+package barcode
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	"github.com/tclutin/shoppinglist-api/internal/domain/barcode"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type Service interface {
+	Lookup(ctx context.Context, dto barcode.LookupDTO) (barcode.BarcodeDTO, error)
+	Learn(ctx context.Context, dto barcode.LearnDTO) (uint64, error)
+	ImportGlobal(ctx context.Context, mappings []barcode.Mapping) (barcode.ImportedDTO, error)
+}
+
+const (
+	// maxImportSize and maxImportedLines limit an imported file
+	maxImportSize    = 1 << 20
+	maxImportedLines = 10000
+)
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewBarcodeHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "barcode_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	productsRouter := router.Group("products", mw.AuthMiddleware(authService), mw.LocaleMiddleware(authService))
+	{
+		productsRouter.GET("/barcode/:code", h.Lookup)
+	}
+
+	groupsRouter := router.Group("groups", mw.AuthMiddleware(authService))
+	{
+		groupsRouter.POST("/:group_id/barcodes", h.Learn)
+	}
+
+	adminRouter := router.Group("admin", mw.AuthMiddleware(authService), mw.AdminMiddleware(authService))
+	{
+		adminRouter.POST("/barcodes/import", h.ImportBarcodes)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Lookup
+// @Description	find the product name of a scanned EAN-13 or UPC-A code, the barcodes learned by your groups first
+// @Tags			barcodes
+// @Accept			json
+// @Produce		json
+// @Param			code	path		string	true	"EAN-13 or UPC-A code"
+// @Param			group_id	query		int	false	"Group ID, only the barcodes learned by this group and the global ones"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
+// @Success		200		{object}	barcode.BarcodeDTO
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/products/barcode/{code} [get]
+func (h *Handler) Lookup(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	var request LookupRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	found, err := h.service.Lookup(c.Request.Context(), barcode.LookupDTO{
+		UserID:  userID.(uint64),
+		GroupID: request.GroupID,
+		Code:    c.Param("code"),
+		Locales: c.GetStringSlice("locales"),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrInvalidBarcode) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrBarcodeNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing Lookup", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, found)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Learn
+// @Description	remember the product name of a code for the group, the barcode learned before is replaced
+// @Tags			barcodes
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		LearnRequest	true	"code and product name"
+// @Success		200		{object}	BarcodeResponse
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/barcodes [post]
+func (h *Handler) Learn(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request LearnRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	barcodeID, err := h.service.Learn(c.Request.Context(), barcode.LearnDTO{
+		GroupID:       groupID,
+		UserID:        userID.(uint64),
+		Code:          request.Code,
+		ProductNameID: request.ProductNameID,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrInvalidBarcode) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing Learn", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, BarcodeResponse{BarcodeID: barcodeID})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		ImportBarcodes
+// @Description	add or replace global barcodes from CSV lines of a code and a global product name id, a header line is allowed
+// @Tags			barcodes
+// @Accept			text/csv
+// @Produce		json
+// @Param			input	body		string	true	"CSV with the code and product_name_id columns"
+// @Success		200		{object}	barcode.ImportedDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		413		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/barcodes/import [post]
+func (h *Handler) ImportBarcodes(c *gin.Context) {
+	reader := csv.NewReader(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var mappings []barcode.Mapping
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.AbortWithStatusJSON(
+					http.StatusRequestEntityTooLarge,
+					response.NewAPIError(http.StatusRequestEntityTooLarge, err.Error(), nil))
+				return
+			}
+
+			c.AbortWithStatusJSON(
+				http.StatusUnprocessableEntity,
+				response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+			return
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if line == 1 && strings.EqualFold(record[0], "code") {
+			continue
+		}
+
+		if len(mappings) == maxImportedLines {
+			c.AbortWithStatusJSON(
+				http.StatusUnprocessableEntity,
+				response.NewAPIError(http.StatusUnprocessableEntity, fmt.Sprintf("more than %d lines", maxImportedLines), nil))
+			return
+		}
+
+		// a line without a valid id gets zero and is skipped as an unknown product name
+		mapping := barcode.Mapping{Line: line, Code: record[0]}
+		if len(record) == 2 {
+			mapping.ProductNameID, _ = strconv.ParseUint(strings.TrimSpace(record[1]), 10, 64)
+		}
+
+		mappings = append(mappings, mapping)
+	}
+
+	imported, err := h.service.ImportGlobal(c.Request.Context(), mappings)
+	if err != nil {
+		h.logger.Error("error occurred while processing ImportBarcodes", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, imported)
+}
@@ -0,0 +1,10 @@
+package barcode
+
+type LookupRequest struct {
+	GroupID uint64 `form:"group_id"`
+}
+
+type LearnRequest struct {
+	Code          string `json:"code" binding:"required,min=12,max=13"`
+	ProductNameID uint64 `json:"product_name_id" binding:"required"`
+}
@@ -0,0 +1,5 @@
+package barcode
+
+type BarcodeResponse struct {
+	BarcodeID uint64 `json:"barcode_id"`
+}
@@ -0,0 +1,449 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	"github.com/tclutin/shoppinglist-api/internal/domain/budget"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Service interface {
+	CreateBudget(ctx context.Context, dto budget.CreateBudgetDTO) (uint64, error)
+	GetBudgets(ctx context.Context, dto budget.GroupBudgetsDTO) ([]budget.BudgetDTO, error)
+	UpdateBudget(ctx context.Context, dto budget.UpdateBudgetDTO) error
+	DeleteBudget(ctx context.Context, dto budget.BudgetUserDTO) error
+	GetAlerts(ctx context.Context, dto budget.GroupAlertsDTO) ([]budget.AlertDTO, error)
+}
+
+const defaultAlertsLimit = 20
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewBudgetHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "budget_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	budgetsRouter := router.Group("groups", mw.AuthMiddleware(authService))
+	{
+		budgetsRouter.POST("/:group_id/budgets", h.CreateBudget)
+		budgetsRouter.GET("/:group_id/budgets", mw.LocaleMiddleware(authService), h.GetBudgets)
+		budgetsRouter.GET("/:group_id/budgets/alerts", mw.LocaleMiddleware(authService), h.GetAlerts)
+		budgetsRouter.PATCH("/:group_id/budgets/:budget_id", h.UpdateBudget)
+		budgetsRouter.DELETE("/:group_id/budgets/:budget_id", h.DeleteBudget)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		CreateBudget
+// @Description	set a monthly budget of the group or of one category, only for the owner
+// @Tags			budgets
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		CreateBudgetRequest	true	"category and amount"
+// @Success		200		{object}	BudgetResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/budgets [post]
+func (h *Handler) CreateBudget(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request CreateBudgetRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	budgetID, err := h.service.CreateBudget(c.Request.Context(), budget.CreateBudgetDTO{
+		GroupID:    groupID,
+		UserID:     userID.(uint64),
+		CategoryID: request.CategoryID,
+		Amount:     request.Amount,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrAreNotOwner) {
+			c.AbortWithStatusJSON(http.StatusForbidden,
+				response.NewAPIError(http.StatusForbidden, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrCategoryNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrBudgetAlreadyExists) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing CreateBudget", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, BudgetResponse{BudgetID: budgetID})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetBudgets
+// @Description	get budgets of the group with the money spent on bought products this month
+// @Tags			budgets
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			Accept-Language	header		string	false	"preferred languages of category names"
+// @Success		200		{object}	budget.BudgetDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/budgets [get]
+func (h *Handler) GetBudgets(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	budgets, err := h.service.GetBudgets(c.Request.Context(), budget.GroupBudgetsDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		Locales: c.GetStringSlice("locales"),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetBudgets", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateBudget
+// @Description	change the amount of a budget, only for the owner
+// @Tags			budgets
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			budget_id	path		string	true	"Budget ID"
+// @Param			input	body		UpdateBudgetRequest	true	"amount"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/budgets/{budget_id} [patch]
+func (h *Handler) UpdateBudget(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	budgetID, err := strconv.ParseUint(c.Param("budget_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':budget_id' is not correct", nil))
+		return
+	}
+
+	var request UpdateBudgetRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.UpdateBudget(c.Request.Context(), budget.UpdateBudgetDTO{
+		GroupID:  groupID,
+		UserID:   userID.(uint64),
+		BudgetID: budgetID,
+		Amount:   request.Amount,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrAreNotOwner) {
+			c.AbortWithStatusJSON(http.StatusForbidden,
+				response.NewAPIError(http.StatusForbidden, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrBudgetNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing UpdateBudget", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		DeleteBudget
+// @Description	delete a budget with its alerts, only for the owner
+// @Tags			budgets
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			budget_id	path		string	true	"Budget ID"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/budgets/{budget_id} [delete]
+func (h *Handler) DeleteBudget(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	budgetID, err := strconv.ParseUint(c.Param("budget_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':budget_id' is not correct", nil))
+		return
+	}
+
+	err = h.service.DeleteBudget(c.Request.Context(), budget.BudgetUserDTO{
+		GroupID:  groupID,
+		UserID:   userID.(uint64),
+		BudgetID: budgetID,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrAreNotOwner) {
+			c.AbortWithStatusJSON(http.StatusForbidden,
+				response.NewAPIError(http.StatusForbidden, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrBudgetNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing DeleteBudget", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetAlerts
+// @Description	get alerts raised when spending of a month reached 80% and 100% of a budget, the latest first
+// @Tags			budgets
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			limit	query		int	false	"page size, 20 by default"
+// @Param			offset	query		int	false	"page offset"
+// @Param			Accept-Language	header		string	false	"preferred languages of category names"
+// @Success		200		{object}	budget.AlertDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/budgets/alerts [get]
+func (h *Handler) GetAlerts(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request GetAlertsRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	if request.Limit == 0 {
+		request.Limit = defaultAlertsLimit
+	}
+
+	alerts, err := h.service.GetAlerts(c.Request.Context(), budget.GroupAlertsDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		Locales: c.GetStringSlice("locales"),
+		Limit:   request.Limit,
+		Offset:  request.Offset,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetAlerts", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
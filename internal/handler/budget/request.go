@@ -0,0 +1,18 @@
+package budget
+
+import "github.com/shopspring/decimal"
+
+type CreateBudgetRequest struct {
+	// CategoryID limits the budget to one category, the budget is for the whole group when omitted
+	CategoryID *uint64         `json:"category_id" binding:"omitempty,gt=0"`
+	Amount     decimal.Decimal `json:"amount" binding:"required,gt=0,max=100000000"`
+}
+
+type UpdateBudgetRequest struct {
+	Amount decimal.Decimal `json:"amount" binding:"required,gt=0,max=100000000"`
+}
+
+type GetAlertsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
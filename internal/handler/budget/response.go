@@ -0,0 +1,5 @@
+package budget
+
+type BudgetResponse struct {
+	BudgetID uint64 `json:"budget_id"`
+}
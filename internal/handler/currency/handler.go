@@ -0,0 +1,109 @@
+package currency
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	"github.com/tclutin/shoppinglist-api/internal/domain/currency"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"log/slog"
+	"net/http"
+	"regexp"
+)
+
+type Service interface {
+	GetRates(ctx context.Context) ([]currency.Rate, error)
+	SetRate(ctx context.Context, currency string, rate decimal.Decimal) error
+}
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewCurrencyHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "currency_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	router.GET("/currencies", mw.AuthMiddleware(authService), h.GetRates)
+
+	adminRouter := router.Group("admin", mw.AuthMiddleware(authService), mw.AdminMiddleware(authService))
+	{
+		adminRouter.PUT("/currencies/:currency", h.SetRate)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetRates
+// @Description	get currencies with their exchange rates to rubles
+// @Tags			currencies
+// @Accept			json
+// @Produce		json
+// @Success		200		{object}	currency.Rate
+// @Failure		401		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/currencies [get]
+func (h *Handler) GetRates(c *gin.Context) {
+	rates, err := h.service.GetRates(c.Request.Context())
+	if err != nil {
+		h.logger.Error("error occurred while processing GetRates", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		SetRate
+// @Description	add a currency or update its exchange rate to rubles
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			currency	path		string	true	"ISO 4217 code"
+// @Param			input	body		SetRateRequest	true	"exchange rate"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/admin/currencies/{currency} [put]
+func (h *Handler) SetRate(c *gin.Context) {
+	code := c.Param("currency")
+	if !currencyCode.MatchString(code) {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':currency' is not correct", nil))
+		return
+	}
+
+	var request SetRateRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	if err := h.service.SetRate(c.Request.Context(), code, request.Rate); err != nil {
+		h.logger.Error("error occurred while processing SetRate", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
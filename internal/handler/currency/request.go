@@ -0,0 +1,8 @@
+package currency
+
+import "github.com/shopspring/decimal"
+
+type SetRateRequest struct {
+	// Rate is the price of one unit of the currency in rubles
+	Rate decimal.Decimal `json:"rate" binding:"required,gt=0"`
+}
@@ -0,0 +1,418 @@
+package expense
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/expense"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Service interface {
+	GetBalances(ctx context.Context, dto expense.GroupUserDTO) (expense.BalancesDTO, error)
+	CreateSettlement(ctx context.Context, dto expense.CreateSettlementDTO) (uint64, error)
+	GetSettlements(ctx context.Context, dto expense.GroupUserDTO) ([]expense.SettlementDTO, error)
+	UpdateShare(ctx context.Context, dto expense.UpdateShareDTO) error
+	UpdateExclusions(ctx context.Context, dto expense.UpdateExclusionsDTO) error
+}
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewExpenseHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "expense_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	expensesRouter := router.Group("groups", mw.AuthMiddleware(authService))
+	{
+		expensesRouter.GET("/:group_id/balances", h.GetBalances)
+		expensesRouter.POST("/:group_id/settlements", h.CreateSettlement)
+		expensesRouter.GET("/:group_id/settlements", h.GetSettlements)
+		expensesRouter.PUT("/:group_id/members/:member_id/share", h.UpdateShare)
+		expensesRouter.PUT("/:group_id/products/:product_id/exclusions", h.UpdateExclusions)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetBalances
+// @Description	get what members paid and owe for bought products and trips, and transfers to settle up
+// @Tags			expenses
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Success		200		{object}	expense.BalancesDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/balances [get]
+func (h *Handler) GetBalances(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	balances, err := h.service.GetBalances(c.Request.Context(), expense.GroupUserDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetBalances", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, balances)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		CreateSettlement
+// @Description	record that you paid money to a member
+// @Tags			expenses
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		CreateSettlementRequest	true	"receiver and amount"
+// @Success		200		{object}	SettlementResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		400		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/settlements [post]
+func (h *Handler) CreateSettlement(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request CreateSettlementRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	settlementID, err := h.service.CreateSettlement(c.Request.Context(), expense.CreateSettlementDTO{
+		GroupID:    groupID,
+		UserID:     userID.(uint64),
+		ToMemberID: request.ToMemberID,
+		Amount:     request.Amount,
+		Currency:   request.Currency,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrCannotSettleWithYourself) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrUnknownCurrency) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing CreateSettlement", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, SettlementResponse{SettlementID: settlementID})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetSettlements
+// @Description	get settlements of group, the latest first
+// @Tags			expenses
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Success		200		{object}	expense.SettlementDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/settlements [get]
+func (h *Handler) GetSettlements(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	settlements, err := h.service.GetSettlements(c.Request.Context(), expense.GroupUserDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetSettlements", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, settlements)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateShare
+// @Description	set the share of a member for the "share" split rule, only for the owner
+// @Tags			expenses
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			member_id	path		string	true	"Member ID"
+// @Param			input	body		UpdateShareRequest	true	"share of the member"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/members/{member_id}/share [put]
+func (h *Handler) UpdateShare(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("member_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':member_id' is not correct", nil))
+		return
+	}
+
+	var request UpdateShareRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.UpdateShare(c.Request.Context(), expense.UpdateShareDTO{
+		GroupID:  groupID,
+		UserID:   userID.(uint64),
+		MemberID: memberID,
+		Share:    *request.Share,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrAreNotOwner) {
+			c.AbortWithStatusJSON(http.StatusForbidden,
+				response.NewAPIError(http.StatusForbidden, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing UpdateShare", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateExclusions
+// @Description	set the members who don't pay for a product
+// @Tags			expenses
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			product_id	path		string	true	"Product ID"
+// @Param			input	body		UpdateExclusionsRequest	true	"excluded members, empty to split between all"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/products/{product_id}/exclusions [put]
+func (h *Handler) UpdateExclusions(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':product_id' is not correct", nil))
+		return
+	}
+
+	var request UpdateExclusionsRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.UpdateExclusions(c.Request.Context(), expense.UpdateExclusionsDTO{
+		GroupID:   groupID,
+		UserID:    userID.(uint64),
+		ProductID: productID,
+		MemberIDs: request.MemberIDs,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing UpdateExclusions", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
@@ -0,0 +1,18 @@
+package expense
+
+import "github.com/shopspring/decimal"
+
+type CreateSettlementRequest struct {
+	ToMemberID uint64          `json:"to_member_id" binding:"required"`
+	Amount     decimal.Decimal `json:"amount" binding:"required,gt=0,max=100000000"`
+	// Currency is the currency of the group when omitted
+	Currency string `json:"currency" binding:"omitempty,iso4217"`
+}
+
+type UpdateShareRequest struct {
+	Share *float64 `json:"share" binding:"required,gte=0,max=1000"`
+}
+
+type UpdateExclusionsRequest struct {
+	MemberIDs []uint64 `json:"member_ids" binding:"max=100,unique"`
+}
@@ -0,0 +1,5 @@
+package expense
+
+type SettlementResponse struct {
+	SettlementID uint64 `json:"settlement_id"`
+}
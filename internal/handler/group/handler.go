@@ -10,6 +10,7 @@ import (
 	"github.com/tclutin/shoppinglist-api/internal/domain/member"
 	"github.com/tclutin/shoppinglist-api/internal/domain/product"
 	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/lineparser"
 	"github.com/tclutin/shoppinglist-api/pkg/logger"
 	"github.com/tclutin/shoppinglist-api/pkg/response"
 	"log/slog"
@@ -24,13 +25,31 @@ type Service interface {
 	LeaveFromGroup(ctx context.Context, dto group.GroupUserDTO) error
 	GetGroupMembers(ctx context.Context, dto group.GroupUserDTO) ([]member.MemberDTO, error)
 	KickMember(ctx context.Context, dto group.KickMemberDTO) error
+	UpdateSettings(ctx context.Context, dto group.UpdateSettingsDTO) error
 
-	AddProduct(ctx context.Context, dto group.CreateProductDTO) (uint64, error)
+	AddProduct(ctx context.Context, dto group.CreateProductDTO) (group.AddedProductDTO, error)
 	RemoveProduct(ctx context.Context, dto group.RemoveProductDTO) error
 	UpdateProduct(ctx context.Context, dto group.UpdateProductDTO) error
-	GetGroupProducts(ctx context.Context, dto group.GroupUserDTO) ([]product.ProductDTO, error)
+	GetGroupProducts(ctx context.Context, dto group.GroupProductsDTO) (product.ProductPageDTO, error)
+	GetProductRequests(ctx context.Context, dto group.ProductRequestsDTO) ([]product.ProductRequestDTO, error)
+	DedupeProducts(ctx context.Context, dto group.GroupUserDTO) (int, error)
+	ClaimProduct(ctx context.Context, dto group.ClaimProductDTO) error
+	ReleaseProduct(ctx context.Context, dto group.ClaimProductDTO) error
+	MoveProduct(ctx context.Context, dto group.MoveProductDTO) error
+
+	AddCatalogProduct(ctx context.Context, dto group.CreateCatalogProductDTO) (uint64, error)
+	GetCatalogProducts(ctx context.Context, dto group.GroupUserDTO) ([]product.ProductName, error)
+
+	ParseProducts(ctx context.Context, dto group.ParseProductsDTO) ([]group.ParsedProductDTO, error)
 }
 
+const (
+	// maxParsedLines limits how many lines of a pasted text are parsed at once
+	maxParsedLines = 100
+
+	defaultProductsLimit = 50
+)
+
 type Handler struct {
 	logger  logger.Logger
 	service Service
@@ -52,11 +71,23 @@ func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
 		groupsRouter.DELETE("/:group_id/leave", h.LeaveFromGroup)
 		groupsRouter.GET("/:group_id/members", h.GetGroupMembers)
 		groupsRouter.DELETE("/:group_id/members/:member_id", h.KickMember)
+		groupsRouter.PATCH("/:group_id/settings", h.UpdateSettings)
 
 		groupsRouter.POST("/:group_id/products", h.AddProduct)
 		groupsRouter.DELETE("/:group_id/products/:product_id", h.RemoveProduct)
 		groupsRouter.PATCH("/:group_id/products/:product_id", h.UpdateProduct)
-		groupsRouter.GET("/:group_id/products", h.GetGroupProducts)
+		groupsRouter.GET("/:group_id/products", mw.LocaleMiddleware(authService), h.GetGroupProducts)
+		groupsRouter.POST("/:group_id/products/parse", mw.LocaleMiddleware(authService), h.ParseProduct)
+		groupsRouter.POST("/:group_id/products/parse/bulk", mw.LocaleMiddleware(authService), h.ParseProducts)
+		groupsRouter.POST("/:group_id/products/dedupe", h.DedupeProducts)
+		groupsRouter.GET("/:group_id/products/:product_id/requests", h.GetProductRequests)
+		groupsRouter.POST("/:group_id/products/:product_id/claim", h.ClaimProduct)
+		groupsRouter.DELETE("/:group_id/products/:product_id/claim", h.ReleaseProduct)
+		groupsRouter.PUT("/:group_id/products/:product_id/assignee", h.AssignProduct)
+		groupsRouter.POST("/:group_id/products/:product_id/move", h.MoveProduct)
+
+		groupsRouter.POST("/:group_id/catalog", h.AddCatalogProduct)
+		groupsRouter.GET("/:group_id/catalog", h.GetCatalogProducts)
 	}
 }
 
@@ -442,7 +473,7 @@ func (h *Handler) KickMember(c *gin.Context) {
 
 // @Security		ApiKeyAuth
 // @Summary		AddProduct
-// @Description	Add product to group
+// @Description	Add product to group, the response has the price estimated from the prices the group paid before
 // @Tags			groups
 // @Accept			json
 // @Produce		json
@@ -450,6 +481,7 @@ func (h *Handler) KickMember(c *gin.Context) {
 // @Param			input	body		CreateProductRequest	true	"add new product to group"
 // @Success		200		{object}	ProductResponse
 // @Failure		401		{object}	response.APIError
+// @Failure		400		{object}	response.APIError
 // @Failure		422		{object}	response.APIError
 // @Failure		404		{object}	response.APIError
 // @Failure		500		{object}	response.APIError
@@ -480,11 +512,18 @@ func (h *Handler) AddProduct(c *gin.Context) {
 		return
 	}
 
-	productID, err := h.service.AddProduct(c.Request.Context(), group.CreateProductDTO{
+	added, err := h.service.AddProduct(c.Request.Context(), group.CreateProductDTO{
 		UserID:        userID.(uint64),
 		GroupID:       groupID,
 		ProductNameID: request.ProductNameID,
+		ProductName:   request.ProductName,
+		CategoryID:    request.CategoryID,
 		Quantity:      request.Quantity,
+		Unit:          request.Unit,
+		Note:          request.Note,
+		Priority:      request.Priority,
+		NeededBy:      parseDate(request.NeededBy),
+		Merge:         request.Merge,
 	})
 
 	if err != nil {
@@ -506,6 +545,30 @@ func (h *Handler) AddProduct(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, domainErr.ErrCategoryNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNameEmpty) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrCategoryRequired) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrInvalidUnit) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
 		h.logger.Error("error occurred while processing AddProduct", slog.Any("error", err))
 		c.AbortWithStatusJSON(
 			http.StatusInternalServerError,
@@ -513,7 +576,11 @@ func (h *Handler) AddProduct(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, ProductResponse{ProductID: productID})
+	c.JSON(http.StatusOK, ProductResponse{
+		ProductID:      added.ProductID,
+		EstimatedPrice: added.EstimatedPrice,
+		Currency:       added.Currency,
+	})
 }
 
 // @Security		ApiKeyAuth
@@ -601,9 +668,11 @@ func (h *Handler) RemoveProduct(c *gin.Context) {
 // @Param			input	body		UpdateProductRequest	true	"update a product"
 // @Success		200		{object}	response.APIResponse
 // @Failure		401		{object}	response.APIError
+// @Failure		400		{object}	response.APIError
 // @Failure		422		{object}	response.APIError
 // @Failure		404		{object}	response.APIError
 // @Failure		500		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
 // @Router			/groups/{group_id}/products/{product_id} [PATCH]
 func (h *Handler) UpdateProduct(c *gin.Context) {
 	userID, ok := c.Get("userID")
@@ -639,16 +708,44 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	err = h.service.UpdateProduct(c.Request.Context(), group.UpdateProductDTO{
+	dto := group.UpdateProductDTO{
 		GroupID:   groupID,
 		ProductID: productID,
 		UserID:    userID.(uint64),
 		Price:     request.Price,
+		Currency:  request.Currency,
 		Quantity:  request.Quantity,
+		Unit:      request.Unit,
 		Status:    request.Status,
-	})
+		Note:      request.Note,
+		Priority:  request.Priority,
+		Force:     request.Force,
+		ToPantry:  request.ToPantry,
+		ExpiresAt: parseDate(request.ExpiresAt),
+	}
+
+	if request.NeededBy != nil {
+		dto.NeededBy = parseDate(*request.NeededBy)
+		dto.ClearNeededBy = *request.NeededBy == ""
+
+		if dto.NeededBy == nil && !dto.ClearNeededBy {
+			c.AbortWithStatusJSON(
+				http.StatusUnprocessableEntity,
+				response.NewAPIError(http.StatusUnprocessableEntity, "'needed_by' is not a date like 2006-01-02", nil))
+			return
+		}
+	}
+
+	err = h.service.UpdateProduct(c.Request.Context(), dto)
 
 	if err != nil {
+		// the product is saved, only its budget alerts are missing
+		if errors.Is(err, domainErr.ErrThresholdsNotChecked) {
+			h.logger.Error("error occurred while checking budget thresholds", slog.Any("error", err))
+			c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+			return
+		}
+
 		if errors.Is(err, domainErr.ErrGroupNotFound) {
 			c.AbortWithStatusJSON(http.StatusNotFound,
 				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
@@ -667,6 +764,24 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, domainErr.ErrInvalidUnit) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrUnknownCurrency) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrClaimedByAnotherMember) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
 		h.logger.Error("error occurred while processing UpdateProduct", slog.Any("error", err))
 		c.AbortWithStatusJSON(
 			http.StatusInternalServerError,
@@ -684,7 +799,23 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 // @Accept			json
 // @Produce		json
 // @Param			group_id	path		string	true	"Group ID"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
+// @Param			status	query		string	false	"open or closed"
+// @Param			category_id	query		int	false	"Category ID"
+// @Param			added_by	query		int	false	"ID of the user who added products"
+// @Param			bought_by	query		int	false	"ID of the user who bought products"
+// @Param			assigned_to	query		int	false	"ID of the user who claimed products"
+// @Param			created_from	query		string	false	"products added since the date, e.g. 2025-02-01"
+// @Param			created_to	query		string	false	"products added until the date inclusive"
+// @Param			q	query		string	false	"part of product name"
+// @Param			priority	query		string	false	"low, normal or urgent"
+// @Param			needed_before	query		string	false	"products needed by the date, e.g. 2025-02-01"
+// @Param			store	query		int	false	"Store ID, lists the open products by the sections of the store unless status or sort is given"
+// @Param			sort	query		string	false	"rank (default, the order products are moved to), created_at, category, name, price, priority, needed_by or store"
+// @Param			cursor	query		string	false	"X-Next-Cursor of the previous page"
+// @Param			limit	query		int	false	"page size, 50 by default"
 // @Success		200		{object}	product.ProductDTO
+// @Header			200		{string}	X-Next-Cursor	"cursor of the next page, absent on the last page"
 // @Failure		401		{object}	response.APIError
 // @Failure		422		{object}	response.APIError
 // @Failure		404		{object}	response.APIError
@@ -707,9 +838,46 @@ func (h *Handler) GetGroupProducts(c *gin.Context) {
 		return
 	}
 
-	products, err := h.service.GetGroupProducts(c.Request.Context(), group.GroupUserDTO{
+	var request GetGroupProductsRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	// created_to includes the whole day
+	createdTo := parseDate(request.CreatedTo)
+	if createdTo != nil {
+		nextDay := createdTo.AddDate(0, 0, 1)
+		createdTo = &nextDay
+	}
+
+	if request.Limit == 0 {
+		request.Limit = defaultProductsLimit
+	}
+
+	page, err := h.service.GetGroupProducts(c.Request.Context(), group.GroupProductsDTO{
 		GroupID: groupID,
 		UserID:  userID.(uint64),
+		Locales: c.GetStringSlice("locales"),
+		Filter: product.ProductFilter{
+			Status:       request.Status,
+			CategoryID:   request.CategoryID,
+			AddedBy:      request.AddedBy,
+			BoughtBy:     request.BoughtBy,
+			AssignedTo:   request.AssignedTo,
+			CreatedFrom:  parseDate(request.CreatedFrom),
+			CreatedTo:    createdTo,
+			Query:        request.Query,
+			Priority:     request.Priority,
+			NeededBefore: parseDate(request.NeededBefore),
+			StoreID:      request.Store,
+			Sort:         request.Sort,
+			Cursor:       request.Cursor,
+			Limit:        request.Limit,
+		},
 	})
 
 	if err != nil {
@@ -725,6 +893,18 @@ func (h *Handler) GetGroupProducts(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, domainErr.ErrStoreNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrInvalidCursor) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity,
+				response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+			return
+		}
+
 		h.logger.Error("error occurred while processing GetGroupProducts", slog.Any("error", err))
 		c.AbortWithStatusJSON(
 			http.StatusInternalServerError,
@@ -732,5 +912,907 @@ func (h *Handler) GetGroupProducts(c *gin.Context) {
 		return
 	}
 
+	if page.NextCursor != "" {
+		c.Header("X-Next-Cursor", page.NextCursor)
+	}
+
+	c.JSON(http.StatusOK, page.Products)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		AddCatalogProduct
+// @Description	add own product name to group catalog
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		CreateCatalogProductRequest	true	"add product name to group catalog"
+// @Success		200		{object}	CatalogProductResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/catalog [post]
+func (h *Handler) AddCatalogProduct(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request CreateCatalogProductRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	productNameID, err := h.service.AddCatalogProduct(c.Request.Context(), group.CreateCatalogProductDTO{
+		UserID:      userID.(uint64),
+		GroupID:     groupID,
+		CategoryID:  request.CategoryID,
+		Name:        request.Name,
+		DefaultUnit: request.DefaultUnit,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrCategoryNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNameAlreadyExists) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing AddCatalogProduct", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, CatalogProductResponse{ProductNameID: productNameID})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetCatalogProducts
+// @Description	get own product names of group
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Success		200		{object}	product.ProductName
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/catalog [get]
+func (h *Handler) GetCatalogProducts(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	products, err := h.service.GetCatalogProducts(c.Request.Context(), group.GroupUserDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetCatalogProducts", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
 	c.JSON(http.StatusOK, products)
 }
+
+// @Security		ApiKeyAuth
+// @Summary		ParseProduct
+// @Description	parse a line like "2 кг картошки" into quantity, unit and catalog suggestions, optionally adding it
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		ParseProductRequest	true	"line to parse"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
+// @Success		200		{object}	group.ParsedProductDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/products/parse [post]
+func (h *Handler) ParseProduct(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request ParseProductRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	lines := lineparser.Split(request.Text)
+	if len(lines) != 1 {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "'text' must contain one product", nil))
+		return
+	}
+
+	parsed, err := h.service.ParseProducts(c.Request.Context(), group.ParseProductsDTO{
+		UserID:  userID.(uint64),
+		GroupID: groupID,
+		Lines:   lines,
+		Create:  request.Create,
+		Locales: c.GetStringSlice("locales"),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing ParseProduct", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, parsed[0])
+}
+
+// @Security		ApiKeyAuth
+// @Summary		ParseProducts
+// @Description	parse pasted text, one product per line, into quantities, units and catalog suggestions, optionally adding them; on a 500 error.body holds the lines handled before, with the products already added
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		ParseProductsRequest	true	"text to parse"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
+// @Success		200		{object}	group.ParsedProductDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/products/parse/bulk [post]
+func (h *Handler) ParseProducts(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request ParseProductsRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	lines := lineparser.Split(request.Text)
+	if len(lines) == 0 || len(lines) > maxParsedLines {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "'text' must contain from 1 to 100 products", nil))
+		return
+	}
+
+	parsed, err := h.service.ParseProducts(c.Request.Context(), group.ParseProductsDTO{
+		UserID:  userID.(uint64),
+		GroupID: groupID,
+		Lines:   lines,
+		Create:  request.Create,
+		Locales: c.GetStringSlice("locales"),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		// the lines before the failed one may be on the list already, the client must not add them again
+		var handled any
+		if len(parsed) > 0 {
+			handled = parsed
+		}
+
+		h.logger.Error("error occurred while processing ParseProducts", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", handled))
+		return
+	}
+
+	c.JSON(http.StatusOK, parsed)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateSettings
+// @Description	update settings of group, only for the owner
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		UpdateSettingsRequest	true	"group settings"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		400		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/settings [patch]
+func (h *Handler) UpdateSettings(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request UpdateSettingsRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.UpdateSettings(c.Request.Context(), group.UpdateSettingsDTO{
+		GroupID:         groupID,
+		UserID:          userID.(uint64),
+		MergeDuplicates: request.MergeDuplicates,
+		SplitRule:       request.SplitRule,
+		Currency:        request.Currency,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrAreNotOwner) {
+			c.AbortWithStatusJSON(http.StatusForbidden,
+				response.NewAPIError(http.StatusForbidden, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrUnknownCurrency) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing UpdateSettings", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		DedupeProducts
+// @Description	merge open products of group with the same name and compatible units into one
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Success		200		{object}	DedupeResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/products/dedupe [post]
+func (h *Handler) DedupeProducts(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	merged, err := h.service.DedupeProducts(c.Request.Context(), group.GroupUserDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing DedupeProducts", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, DedupeResponse{Merged: merged})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetProductRequests
+// @Description	get who requested a product and how much
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			product_id	path		string	true	"Product ID"
+// @Success		200		{object}	product.ProductRequestDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/products/{product_id}/requests [get]
+func (h *Handler) GetProductRequests(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':product_id' is not correct", nil))
+		return
+	}
+
+	requests, err := h.service.GetProductRequests(c.Request.Context(), group.ProductRequestsDTO{
+		ProductID: productID,
+		GroupID:   groupID,
+		UserID:    userID.(uint64),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetProductRequests", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		ClaimProduct
+// @Description	claim an open product to buy it
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			product_id	path		string	true	"Product ID"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/products/{product_id}/claim [post]
+func (h *Handler) ClaimProduct(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':product_id' is not correct", nil))
+		return
+	}
+
+	err = h.service.ClaimProduct(c.Request.Context(), group.ClaimProductDTO{
+		ProductID: productID,
+		GroupID:   groupID,
+		UserID:    userID.(uint64),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrAreNotOwner) {
+			c.AbortWithStatusJSON(http.StatusForbidden,
+				response.NewAPIError(http.StatusForbidden, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrClaimedByAnotherMember) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductClosed) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing ClaimProduct", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		ReleaseProduct
+// @Description	release a claimed product, only for the assignee and the owner
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			product_id	path		string	true	"Product ID"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/products/{product_id}/claim [delete]
+func (h *Handler) ReleaseProduct(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':product_id' is not correct", nil))
+		return
+	}
+
+	err = h.service.ReleaseProduct(c.Request.Context(), group.ClaimProductDTO{
+		ProductID: productID,
+		GroupID:   groupID,
+		UserID:    userID.(uint64),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrClaimedByAnotherMember) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing ReleaseProduct", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		AssignProduct
+// @Description	assign an open product to a member, only for the owner
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			product_id	path		string	true	"Product ID"
+// @Param			input	body		AssignProductRequest	true	"member to assign the product to"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		403		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/products/{product_id}/assignee [put]
+func (h *Handler) AssignProduct(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':product_id' is not correct", nil))
+		return
+	}
+
+	var request AssignProductRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.ClaimProduct(c.Request.Context(), group.ClaimProductDTO{
+		ProductID: productID,
+		GroupID:   groupID,
+		UserID:    userID.(uint64),
+		MemberID:  request.MemberID,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrAreNotOwner) {
+			c.AbortWithStatusJSON(http.StatusForbidden,
+				response.NewAPIError(http.StatusForbidden, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrClaimedByAnotherMember) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductClosed) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing AssignProduct", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		MoveProduct
+// @Description	put a product right before or right after another product of the group
+// @Tags			groups
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			product_id	path		string	true	"Product ID"
+// @Param			input	body		MoveProductRequest	true	"product to put it next to"
+// @Success		200		{object}	response.APIResponse
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/products/{product_id}/move [post]
+func (h *Handler) MoveProduct(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':product_id' is not correct", nil))
+		return
+	}
+
+	var request MoveProductRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.MoveProduct(c.Request.Context(), group.MoveProductDTO{
+		ProductID: productID,
+		GroupID:   groupID,
+		UserID:    userID.(uint64),
+		Before:    request.Before,
+		After:     request.After,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrCannotMoveNextToItself) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing MoveProduct", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
@@ -1,5 +1,13 @@
 package group
 
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// dateLayout is the format of dates in requests, e.g. "2025-02-01"
+const dateLayout = "2006-01-02"
+
 type CreateGroupRequest struct {
 	Name        string `json:"name" binding:"required,min=3,max=100"`
 	Description string `json:"description" binding:"required,max=255"`
@@ -10,12 +18,94 @@ type JoinToGroupRequest struct {
 }
 
 type CreateProductRequest struct {
-	ProductNameID uint64 `json:"product_name_id" binding:"required"`
-	Quantity      int    `json:"quantity" binding:"required,min=1,max=1000"`
+	ProductNameID uint64  `json:"product_name_id" binding:"required_without=ProductName"`
+	ProductName   string  `json:"product_name" binding:"required_without=ProductNameID,omitempty,max=100"`
+	CategoryID    uint64  `json:"category_id"`
+	Quantity      float64 `json:"quantity" binding:"required,gt=0,max=100000"`
+	Unit          string  `json:"unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
+	Note          string  `json:"note" binding:"max=500"`
+	Priority      string  `json:"priority" binding:"omitempty,oneof=low normal urgent"`
+	NeededBy      string  `json:"needed_by" binding:"omitempty,datetime=2006-01-02"`
+	Merge         *bool   `json:"merge"`
+}
+
+type CreateCatalogProductRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	CategoryID  uint64 `json:"category_id" binding:"required"`
+	DefaultUnit string `json:"default_unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
 }
 
 type UpdateProductRequest struct {
-	Price    *float64 `json:"price"`
-	Quantity int      `json:"quantity" binding:"required,min=1,max=1000"`
-	Status   string   `json:"status" binding:"required,oneof=open closed"`
+	Price *decimal.Decimal `json:"price" binding:"omitempty,gte=0"`
+	// Currency of the price, the current one is kept when omitted
+	Currency string  `json:"currency" binding:"omitempty,iso4217"`
+	Quantity float64 `json:"quantity" binding:"required,gt=0,max=100000"`
+	Unit     string  `json:"unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
+	Status   string  `json:"status" binding:"required,oneof=open closed"`
+	Note     *string `json:"note" binding:"omitempty,max=500"`
+	Priority string  `json:"priority" binding:"omitempty,oneof=low normal urgent"`
+	// NeededBy is kept when omitted and removed when empty, the handler parses the date
+	NeededBy *string `json:"needed_by"`
+	// Force closes the product even if another member has claimed it
+	Force bool `json:"force"`
+	// ToPantry adds the product to the pantry of the group when it is bought
+	ToPantry  bool   `json:"to_pantry"`
+	ExpiresAt string `json:"expires_at" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type AssignProductRequest struct {
+	MemberID uint64 `json:"member_id" binding:"required"`
+}
+
+// MoveProductRequest takes exactly one of the products to put the moved one next to
+type MoveProductRequest struct {
+	Before uint64 `json:"before" binding:"required_without=After,excluded_with=After"`
+	After  uint64 `json:"after"`
+}
+
+type GetGroupProductsRequest struct {
+	Status       string `form:"status" binding:"omitempty,oneof=open closed"`
+	CategoryID   uint64 `form:"category_id"`
+	AddedBy      uint64 `form:"added_by"`
+	BoughtBy     uint64 `form:"bought_by"`
+	AssignedTo   uint64 `form:"assigned_to"`
+	CreatedFrom  string `form:"created_from" binding:"omitempty,datetime=2006-01-02"`
+	CreatedTo    string `form:"created_to" binding:"omitempty,datetime=2006-01-02"`
+	Query        string `form:"q" binding:"max=100"`
+	Priority     string `form:"priority" binding:"omitempty,oneof=low normal urgent"`
+	NeededBefore string `form:"needed_before" binding:"omitempty,datetime=2006-01-02"`
+	Store        uint64 `form:"store" binding:"required_if=Sort store"`
+	Sort         string `form:"sort" binding:"omitempty,oneof=rank created_at category name price priority needed_by store"`
+	Cursor       string `form:"cursor" binding:"max=1000"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+type ParseProductRequest struct {
+	Text   string `json:"text" binding:"required,max=200"`
+	Create bool   `json:"create"`
+}
+
+type ParseProductsRequest struct {
+	Text   string `json:"text" binding:"required,max=5000"`
+	Create bool   `json:"create"`
+}
+
+type UpdateSettingsRequest struct {
+	MergeDuplicates *bool  `json:"merge_duplicates"`
+	SplitRule       string `json:"split_rule" binding:"omitempty,oneof=equal share"`
+	Currency        string `json:"currency" binding:"omitempty,iso4217"`
+}
+
+// parseDate parses an already validated date, an empty one is nil
+func parseDate(text string) *time.Time {
+	if text == "" {
+		return nil
+	}
+
+	date, err := time.Parse(dateLayout, text)
+	if err != nil {
+		return nil
+	}
+
+	return &date
 }
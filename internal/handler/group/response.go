@@ -1,9 +1,21 @@
 package group
 
+import "github.com/shopspring/decimal"
+
 type GroupResponse struct {
 	GroupID uint64 `json:"group_id"`
 }
 
 type ProductResponse struct {
-	ProductID uint64 `json:"product_id"`
+	ProductID      uint64           `json:"product_id"`
+	EstimatedPrice *decimal.Decimal `json:"estimated_price"`
+	Currency       string           `json:"currency"`
+}
+
+type CatalogProductResponse struct {
+	ProductNameID uint64 `json:"product_name_id"`
+}
+
+type DedupeResponse struct {
+	Merged int `json:"merged"`
 }
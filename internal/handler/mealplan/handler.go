@@ -0,0 +1,471 @@
+package mealplan
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/mealplan"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Service interface {
+	CreateEntry(ctx context.Context, dto mealplan.CreateEntryDTO) (uint64, error)
+	GetPlan(ctx context.Context, dto mealplan.PeriodDTO) ([]mealplan.EntryDTO, error)
+	UpdateEntry(ctx context.Context, dto mealplan.UpdateEntryDTO) error
+	DeleteEntry(ctx context.Context, dto mealplan.EntryUserDTO) error
+	GenerateList(ctx context.Context, dto mealplan.PeriodDTO) (mealplan.GeneratedListDTO, error)
+}
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewMealPlanHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "meal_plan_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	mealPlanRouter := router.Group("groups", mw.AuthMiddleware(authService))
+	{
+		mealPlanRouter.POST("/:group_id/meal-plan", h.CreateEntry)
+		mealPlanRouter.GET("/:group_id/meal-plan", h.GetPlan)
+		mealPlanRouter.POST("/:group_id/meal-plan/generate-list", h.GenerateList)
+		mealPlanRouter.PATCH("/:group_id/meal-plan/:entry_id", h.UpdateEntry)
+		mealPlanRouter.DELETE("/:group_id/meal-plan/:entry_id", h.DeleteEntry)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		CreateEntry
+// @Description	plan a recipe or a meal described by text for a day and a slot
+// @Tags			meal-plan
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		CreateEntryRequest	true	"day, slot and recipe or text"
+// @Success		200		{object}	EntryResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/meal-plan [post]
+func (h *Handler) CreateEntry(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request CreateEntryRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	entryID, err := h.service.CreateEntry(c.Request.Context(), mealplan.CreateEntryDTO{
+		GroupID:  groupID,
+		UserID:   userID.(uint64),
+		Date:     *parseDate(request.Date),
+		Slot:     request.Slot,
+		RecipeID: request.RecipeID,
+		Servings: request.Servings,
+		Text:     request.Text,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrRecipeNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing CreateEntry", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, EntryResponse{EntryID: entryID})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetPlan
+// @Description	get the meal plan of the group for the days from the first to the last, by day and slot
+// @Tags			meal-plan
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			from	query		string	true	"first day, e.g. 2025-03-17"
+// @Param			to	query		string	true	"last day, e.g. 2025-03-23"
+// @Success		200		{object}	mealplan.EntryDTO
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/meal-plan [get]
+func (h *Handler) GetPlan(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request PeriodRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	entries, err := h.service.GetPlan(c.Request.Context(), mealplan.PeriodDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		From:    *parseDate(request.From),
+		To:      *parseDate(request.To),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrInvalidPeriod) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetPlan", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateEntry
+// @Description	move an entry of the meal plan to another day or slot, change its recipe, servings or text
+// @Tags			meal-plan
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			entry_id	path		string	true	"Meal plan entry ID"
+// @Param			input	body		UpdateEntryRequest	true	"fields to change"
+// @Success		200		{object}	response.APIResponse
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/meal-plan/{entry_id} [patch]
+func (h *Handler) UpdateEntry(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	entryID, err := strconv.ParseUint(c.Param("entry_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':entry_id' is not correct", nil))
+		return
+	}
+
+	var request UpdateEntryRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.UpdateEntry(c.Request.Context(), mealplan.UpdateEntryDTO{
+		GroupID:     groupID,
+		UserID:      userID.(uint64),
+		EntryID:     entryID,
+		Date:        parseDate(request.Date),
+		Slot:        request.Slot,
+		RecipeID:    request.RecipeID,
+		Servings:    request.Servings,
+		Text:        request.Text,
+		ClearRecipe: request.ClearRecipe,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMealPlanEntryNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrRecipeNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMealPlanEntryEmpty) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing UpdateEntry", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		DeleteEntry
+// @Description	remove an entry from the meal plan
+// @Tags			meal-plan
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			entry_id	path		string	true	"Meal plan entry ID"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/meal-plan/{entry_id} [delete]
+func (h *Handler) DeleteEntry(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	entryID, err := strconv.ParseUint(c.Param("entry_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':entry_id' is not correct", nil))
+		return
+	}
+
+	err = h.service.DeleteEntry(c.Request.Context(), mealplan.EntryUserDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		EntryID: entryID,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMealPlanEntryNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing DeleteEntry", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GenerateList
+// @Description	add what the recipes planned for the days need to the list, without what the open products and the pantry have; generating it again adds only what the plan gained
+// @Tags			meal-plan
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			from	query		string	true	"first day, e.g. 2025-03-17"
+// @Param			to	query		string	true	"last day, e.g. 2025-03-23"
+// @Success		200		{object}	GenerateListResponse
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/meal-plan/generate-list [post]
+func (h *Handler) GenerateList(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request PeriodRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	generated, err := h.service.GenerateList(c.Request.Context(), mealplan.PeriodDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		From:    *parseDate(request.From),
+		To:      *parseDate(request.To),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrInvalidPeriod) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GenerateList", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	products := make([]ProductResponse, 0, len(generated.Products))
+	for _, product := range generated.Products {
+		products = append(products, ProductResponse{
+			ProductID:      product.ProductID,
+			EstimatedPrice: product.EstimatedPrice,
+			Currency:       product.Currency,
+		})
+	}
+
+	c.JSON(http.StatusOK, GenerateListResponse{
+		Products: products,
+		Covered:  generated.Covered,
+	})
+}
@@ -0,0 +1,43 @@
+package mealplan
+
+import "time"
+
+const dateLayout = "2006-01-02"
+
+// CreateEntryRequest needs a recipe or text, servings are the servings of the recipe when omitted
+type CreateEntryRequest struct {
+	Date     string `json:"date" binding:"required,datetime=2006-01-02"`
+	Slot     string `json:"slot" binding:"required,oneof=breakfast lunch dinner snack"`
+	RecipeID uint64 `json:"recipe_id" binding:"required_without=Text"`
+	Servings int    `json:"servings" binding:"omitempty,min=1,max=100"`
+	Text     string `json:"text" binding:"required_without=RecipeID,max=500"`
+}
+
+// UpdateEntryRequest changes only the given fields, clear_recipe turns the entry into its text
+type UpdateEntryRequest struct {
+	Date        string  `json:"date" binding:"omitempty,datetime=2006-01-02"`
+	Slot        string  `json:"slot" binding:"omitempty,oneof=breakfast lunch dinner snack"`
+	RecipeID    uint64  `json:"recipe_id"`
+	Servings    int     `json:"servings" binding:"omitempty,min=1,max=100"`
+	Text        *string `json:"text" binding:"omitempty,max=500"`
+	ClearRecipe bool    `json:"clear_recipe" binding:"excluded_with=RecipeID"`
+}
+
+// PeriodRequest selects the days from from to to, both included
+type PeriodRequest struct {
+	From string `form:"from" binding:"required,datetime=2006-01-02"`
+	To   string `form:"to" binding:"required,datetime=2006-01-02"`
+}
+
+func parseDate(text string) *time.Time {
+	if text == "" {
+		return nil
+	}
+
+	date, err := time.Parse(dateLayout, text)
+	if err != nil {
+		return nil
+	}
+
+	return &date
+}
@@ -0,0 +1,20 @@
+package mealplan
+
+import "github.com/shopspring/decimal"
+
+type EntryResponse struct {
+	EntryID uint64 `json:"entry_id"`
+}
+
+type ProductResponse struct {
+	ProductID      uint64           `json:"product_id"`
+	EstimatedPrice *decimal.Decimal `json:"estimated_price"`
+	Currency       string           `json:"currency"`
+}
+
+// GenerateListResponse lists the added or merged products, covered are the product names
+// the list and the pantry have enough of
+type GenerateListResponse struct {
+	Products []ProductResponse `json:"products"`
+	Covered  []uint64          `json:"covered_product_name_ids"`
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
 	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/pkg/locale"
 	"github.com/tclutin/shoppinglist-api/pkg/response"
 	"net/http"
 	"strings"
@@ -37,12 +38,57 @@ func AuthMiddleware(authService *auth.Service) gin.HandlerFunc {
 	}
 }
 
+func AdminMiddleware(authService *auth.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get("userID")
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized,
+				response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+			return
+		}
+
+		usr, err := authService.Who(c.Request.Context(), userID.(uint64))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized,
+				response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+			return
+		}
+
+		if !usr.IsAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden,
+				response.NewAPIError(http.StatusForbidden, domainErr.ErrAreNotAdmin.Error(), nil))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// LocaleMiddleware sets "locales" in the order the names of the catalog should be looked up:
+// the locales of Accept-Language header and then the locale from the user's profile
+func LocaleMiddleware(authService *auth.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locales := locale.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+
+		if userID, ok := c.Get("userID"); ok {
+			usr, err := authService.Who(c.Request.Context(), userID.(uint64))
+			if err == nil && usr.Locale != "" {
+				locales = append(locales, usr.Locale)
+			}
+		}
+
+		c.Set("locales", locales)
+		c.Next()
+	}
+}
+
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Next-Cursor")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -0,0 +1,500 @@
+package pantry
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/group"
+	"github.com/tclutin/shoppinglist-api/internal/domain/pantry"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Service interface {
+	AddItem(ctx context.Context, dto pantry.AddItemDTO) (uint64, error)
+	GetPantry(ctx context.Context, dto pantry.GroupPantryDTO) ([]pantry.ItemDTO, error)
+	UpdateItem(ctx context.Context, dto pantry.UpdateItemDTO) (*group.AddedProductDTO, error)
+	DeleteItem(ctx context.Context, dto pantry.ItemUserDTO) error
+	Consume(ctx context.Context, dto pantry.ConsumeDTO) (pantry.ConsumedDTO, error)
+}
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewPantryHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "pantry_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	pantryRouter := router.Group("groups", mw.AuthMiddleware(authService))
+	{
+		pantryRouter.POST("/:group_id/pantry", h.AddItem)
+		pantryRouter.GET("/:group_id/pantry", mw.LocaleMiddleware(authService), h.GetPantry)
+		pantryRouter.PATCH("/:group_id/pantry/:pantry_item_id", h.UpdateItem)
+		pantryRouter.DELETE("/:group_id/pantry/:pantry_item_id", h.DeleteItem)
+		pantryRouter.POST("/:group_id/pantry/:pantry_item_id/consume", h.Consume)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		AddItem
+// @Description	add stock of a product name to the pantry of the group, stock in a compatible unit is added up
+// @Tags			pantry
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		AddItemRequest	true	"product name, quantity and expiry date"
+// @Success		200		{object}	PantryItemResponse
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/pantry [post]
+func (h *Handler) AddItem(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request AddItemRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	pantryItemID, err := h.service.AddItem(c.Request.Context(), pantry.AddItemDTO{
+		GroupID:       groupID,
+		UserID:        userID.(uint64),
+		ProductNameID: request.ProductNameID,
+		Quantity:      request.Quantity,
+		Unit:          request.Unit,
+		ExpiresAt:     parseDate(request.ExpiresAt),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrInvalidUnit) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing AddItem", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, PantryItemResponse{PantryItemID: pantryItemID})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetPantry
+// @Description	get the pantry of the group, the items expiring first come first
+// @Tags			pantry
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			expiring_within_days	query		int	false	"only the items expiring in that many days, including the expired ones"
+// @Param			low_stock	query		bool	false	"only the items at or below their low stock threshold"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
+// @Success		200		{object}	pantry.ItemDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/pantry [get]
+func (h *Handler) GetPantry(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request GetPantryRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	items, err := h.service.GetPantry(c.Request.Context(), pantry.GroupPantryDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		Locales: c.GetStringSlice("locales"),
+		Filter: pantry.Filter{
+			ExpiringWithinDays: request.ExpiringWithinDays,
+			LowStock:           request.LowStock,
+		},
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetPantry", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateItem
+// @Description	correct the stock or the expiry date of a pantry item, set or remove its low stock threshold
+// @Tags			pantry
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			pantry_item_id	path		string	true	"Pantry item ID"
+// @Param			input	body		UpdateItemRequest	true	"fields to change"
+// @Success		200		{object}	UpdateItemResponse
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/pantry/{pantry_item_id} [patch]
+func (h *Handler) UpdateItem(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	pantryItemID, err := strconv.ParseUint(c.Param("pantry_item_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':pantry_item_id' is not correct", nil))
+		return
+	}
+
+	var request UpdateItemRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	dto := pantry.UpdateItemDTO{
+		GroupID:                groupID,
+		UserID:                 userID.(uint64),
+		PantryItemID:           pantryItemID,
+		Quantity:               request.Quantity,
+		LowStockThreshold:      request.LowStockThreshold,
+		RestockQuantity:        request.RestockQuantity,
+		ClearLowStockThreshold: request.ClearLowStockThreshold,
+	}
+
+	if request.ExpiresAt != nil {
+		dto.ExpiresAt = parseDate(*request.ExpiresAt)
+		dto.ClearExpiresAt = *request.ExpiresAt == ""
+	}
+
+	restocked, err := h.service.UpdateItem(c.Request.Context(), dto)
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrPantryItemNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrRestockQuantityRequired) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing UpdateItem", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	resp := UpdateItemResponse{Message: "success"}
+
+	if restocked != nil {
+		resp.Restocked = &ProductResponse{
+			ProductID:      restocked.ProductID,
+			EstimatedPrice: restocked.EstimatedPrice,
+			Currency:       restocked.Currency,
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		DeleteItem
+// @Description	remove an item from the pantry of the group
+// @Tags			pantry
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			pantry_item_id	path		string	true	"Pantry item ID"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/pantry/{pantry_item_id} [delete]
+func (h *Handler) DeleteItem(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	pantryItemID, err := strconv.ParseUint(c.Param("pantry_item_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':pantry_item_id' is not correct", nil))
+		return
+	}
+
+	err = h.service.DeleteItem(c.Request.Context(), pantry.ItemUserDTO{
+		GroupID:      groupID,
+		UserID:       userID.(uint64),
+		PantryItemID: pantryItemID,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrPantryItemNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing DeleteItem", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Consume
+// @Description	take a quantity out of the stock, the product is put on the list when the stock drops to the low stock threshold
+// @Tags			pantry
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			pantry_item_id	path		string	true	"Pantry item ID"
+// @Param			input	body		ConsumeRequest	true	"consumed quantity"
+// @Success		200		{object}	ConsumeResponse
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/pantry/{pantry_item_id}/consume [post]
+func (h *Handler) Consume(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	pantryItemID, err := strconv.ParseUint(c.Param("pantry_item_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':pantry_item_id' is not correct", nil))
+		return
+	}
+
+	var request ConsumeRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	consumed, err := h.service.Consume(c.Request.Context(), pantry.ConsumeDTO{
+		GroupID:      groupID,
+		UserID:       userID.(uint64),
+		PantryItemID: pantryItemID,
+		Quantity:     request.Quantity,
+		Unit:         request.Unit,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrPantryItemNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrInvalidUnit) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing Consume", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	resp := ConsumeResponse{
+		Quantity: consumed.Quantity,
+		Unit:     consumed.Unit,
+	}
+
+	if consumed.Restocked != nil {
+		resp.Restocked = &ProductResponse{
+			ProductID:      consumed.Restocked.ProductID,
+			EstimatedPrice: consumed.Restocked.EstimatedPrice,
+			Currency:       consumed.Restocked.Currency,
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
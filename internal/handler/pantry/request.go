@@ -0,0 +1,47 @@
+package pantry
+
+import "time"
+
+const dateLayout = "2006-01-02"
+
+type AddItemRequest struct {
+	ProductNameID uint64  `json:"product_name_id" binding:"required"`
+	Quantity      float64 `json:"quantity" binding:"required,gt=0,max=100000"`
+	Unit          string  `json:"unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
+	ExpiresAt     string  `json:"expires_at" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type GetPantryRequest struct {
+	// ExpiringWithinDays keeps the items that expire in that many days, including the expired ones
+	ExpiringWithinDays int  `form:"expiring_within_days" binding:"omitempty,min=1,max=365"`
+	LowStock           bool `form:"low_stock"`
+}
+
+// UpdateItemRequest changes only the given fields, the restock quantity is needed to set a threshold
+type UpdateItemRequest struct {
+	Quantity *float64 `json:"quantity" binding:"omitempty,gte=0,max=100000"`
+	// ExpiresAt is kept when omitted and removed when empty
+	ExpiresAt              *string  `json:"expires_at" binding:"omitempty,datetime=2006-01-02"`
+	LowStockThreshold      *float64 `json:"low_stock_threshold" binding:"omitempty,gte=0,max=100000"`
+	RestockQuantity        *float64 `json:"restock_quantity" binding:"omitempty,gt=0,max=100000"`
+	ClearLowStockThreshold bool     `json:"clear_low_stock_threshold" binding:"excluded_with=LowStockThreshold"`
+}
+
+// ConsumeRequest takes the quantity out of the stock, the unit of the item is used when unit is omitted
+type ConsumeRequest struct {
+	Quantity float64 `json:"quantity" binding:"required,gt=0,max=100000"`
+	Unit     string  `json:"unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
+}
+
+func parseDate(text string) *time.Time {
+	if text == "" {
+		return nil
+	}
+
+	date, err := time.Parse(dateLayout, text)
+	if err != nil {
+		return nil
+	}
+
+	return &date
+}
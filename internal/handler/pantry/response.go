@@ -0,0 +1,26 @@
+package pantry
+
+import "github.com/shopspring/decimal"
+
+type PantryItemResponse struct {
+	PantryItemID uint64 `json:"pantry_item_id"`
+}
+
+type ProductResponse struct {
+	ProductID      uint64           `json:"product_id"`
+	EstimatedPrice *decimal.Decimal `json:"estimated_price"`
+	Currency       string           `json:"currency"`
+}
+
+// ConsumeResponse is the stock left, restocked is the product put on the list when the stock ran low
+type ConsumeResponse struct {
+	Quantity  float64          `json:"quantity"`
+	Unit      string           `json:"unit"`
+	Restocked *ProductResponse `json:"restocked"`
+}
+
+// UpdateItemResponse has restocked set when the change made the stock low
+type UpdateItemResponse struct {
+	Message   string           `json:"message"`
+	Restocked *ProductResponse `json:"restocked"`
+}
@@ -0,0 +1,130 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/price"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Service interface {
+	GetPrices(ctx context.Context, dto price.ProductPricesDTO) (price.PricesDTO, error)
+}
+
+const defaultHistoryLimit = 20
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewPriceHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "price_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	pricesRouter := router.Group("groups", mw.AuthMiddleware(authService))
+	{
+		pricesRouter.GET("/:group_id/catalog/:product_name_id/prices", h.GetPrices)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetPrices
+// @Description	get min, average, max and last price per unit the group paid for a product name, the trend of the price and the latest prices
+// @Tags			prices
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			product_name_id	path		string	true	"Product name ID"
+// @Param			limit	query		int	false	"number of latest prices per unit, 20 by default"
+// @Success		200		{object}	price.PricesDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/catalog/{product_name_id}/prices [get]
+func (h *Handler) GetPrices(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	productNameID, err := strconv.ParseUint(c.Param("product_name_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':product_name_id' is not correct", nil))
+		return
+	}
+
+	var request GetPricesRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	if request.Limit == 0 {
+		request.Limit = defaultHistoryLimit
+	}
+
+	prices, err := h.service.GetPrices(c.Request.Context(), price.ProductPricesDTO{
+		GroupID:       groupID,
+		UserID:        userID.(uint64),
+		ProductNameID: productNameID,
+		Limit:         request.Limit,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetPrices", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
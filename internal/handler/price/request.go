@@ -0,0 +1,5 @@
+package price
+
+type GetPricesRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
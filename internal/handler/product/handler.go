@@ -2,8 +2,10 @@ package product
 
 import (
 	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
 	"github.com/tclutin/shoppinglist-api/internal/domain/product"
 	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
 	"github.com/tclutin/shoppinglist-api/pkg/logger"
@@ -14,10 +16,13 @@ import (
 )
 
 type Service interface {
-	GetProductsByCategoryId(ctx context.Context, categoryID uint64) ([]product.ProductName, error)
-	GetCategories(ctx context.Context) ([]product.Category, error)
+	GetProductsByCategoryId(ctx context.Context, dto product.CategoryProductsDTO) ([]product.ProductName, error)
+	GetCategories(ctx context.Context, locales []string) ([]product.Category, error)
+	Search(ctx context.Context, dto product.SearchDTO) ([]product.SearchResultDTO, error)
 }
 
+const defaultSearchLimit = 20
+
 type Handler struct {
 	logger  logger.Logger
 	service Service
@@ -31,9 +36,10 @@ func NewGroupHandler(logger logger.Logger, service Service) *Handler {
 }
 
 func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
-	productGroup := router.Group("/products", mw.AuthMiddleware(authService))
+	productGroup := router.Group("/products", mw.AuthMiddleware(authService), mw.LocaleMiddleware(authService))
 	{
 		productGroup.GET("/categories", h.GetCategories)
+		productGroup.GET("/search", h.Search)
 		productGroup.GET("/:category_id", h.GetProductsByCategoryId)
 	}
 }
@@ -44,12 +50,13 @@ func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
 // @Tags			products
 // @Accept			json
 // @Produce		json
+// @Param			Accept-Language	header		string	false	"preferred languages of category names"
 // @Success		200		{object}	product.Category
 // @Failure		401		{object}	response.APIError
 // @Failure		500		{object}	response.APIError
 // @Router			/products/categories [get]
 func (h *Handler) GetCategories(c *gin.Context) {
-	categories, err := h.service.GetCategories(c.Request.Context())
+	categories, err := h.service.GetCategories(c.Request.Context(), c.GetStringSlice("locales"))
 	if err != nil {
 		h.logger.Error("error occurred while processing GetCategories", slog.Any("error", err))
 		c.AbortWithStatusJSON(
@@ -63,17 +70,28 @@ func (h *Handler) GetCategories(c *gin.Context) {
 
 // @Security		ApiKeyAuth
 // @Summary		GetProductsByCategory
-// @Description	get products by category id
+// @Description	get products by category id, including own product names of the group if group_id is passed
 // @Tags			products
 // @Accept			json
 // @Produce		json
 // @Param			category_id	path		string	true	"category_id"
+// @Param			group_id	query		string	false	"Group ID"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
 // @Success		200		{object}	product.ProductName
 // @Failure		401		{object}	response.APIError
 // @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
 // @Failure		500		{object}	response.APIError
 // @Router			/products/{category_id} [get]
 func (h *Handler) GetProductsByCategoryId(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
 	categoryID, err := strconv.ParseUint(c.Param("category_id"), 10, 64)
 	if err != nil {
 		c.AbortWithStatusJSON(
@@ -82,8 +100,31 @@ func (h *Handler) GetProductsByCategoryId(c *gin.Context) {
 		return
 	}
 
-	products, err := h.service.GetProductsByCategoryId(c.Request.Context(), categoryID)
+	var groupID uint64
+	if rawGroupID := c.Query("group_id"); rawGroupID != "" {
+		groupID, err = strconv.ParseUint(rawGroupID, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(
+				http.StatusUnprocessableEntity,
+				response.NewAPIError(http.StatusUnprocessableEntity, "'group_id' is not correct", nil))
+			return
+		}
+	}
+
+	products, err := h.service.GetProductsByCategoryId(c.Request.Context(), product.CategoryProductsDTO{
+		CategoryID: categoryID,
+		GroupID:    groupID,
+		UserID:     userID.(uint64),
+		Locales:    c.GetStringSlice("locales"),
+	})
+
 	if err != nil {
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
 		h.logger.Error("error occurred while processing GetProductsByCategoryId", slog.Any("error", err))
 		c.AbortWithStatusJSON(
 			http.StatusInternalServerError,
@@ -93,3 +134,59 @@ func (h *Handler) GetProductsByCategoryId(c *gin.Context) {
 
 	c.JSON(http.StatusOK, products)
 }
+
+// @Security		ApiKeyAuth
+// @Summary		Search
+// @Description	search product names by prefix, with typos or in transliteration, popular in your groups first
+// @Tags			products
+// @Accept			json
+// @Produce		json
+// @Param			q	query		string	true	"search text"
+// @Param			limit	query		int	false	"page size, 20 by default"
+// @Param			offset	query		int	false	"page offset"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
+// @Success		200		{object}	product.SearchResultDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/products/search [get]
+func (h *Handler) Search(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	var request SearchRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	if request.Limit == 0 {
+		request.Limit = defaultSearchLimit
+	}
+
+	results, err := h.service.Search(c.Request.Context(), product.SearchDTO{
+		UserID:  userID.(uint64),
+		Query:   request.Query,
+		Locales: c.GetStringSlice("locales"),
+		Limit:   request.Limit,
+		Offset:  request.Offset,
+	})
+
+	if err != nil {
+		h.logger.Error("error occurred while processing Search", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
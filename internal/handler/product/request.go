@@ -0,0 +1,7 @@
+package product
+
+type SearchRequest struct {
+	Query  string `form:"q" binding:"required,max=100"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
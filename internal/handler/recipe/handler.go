@@ -0,0 +1,558 @@
+package recipe
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/recipe"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type Service interface {
+	CreateRecipe(ctx context.Context, dto recipe.CreateRecipeDTO) (uint64, error)
+	GetRecipes(ctx context.Context, dto recipe.GroupRecipesDTO) ([]recipe.RecipeDTO, error)
+	GetRecipe(ctx context.Context, dto recipe.RecipeUserDTO) (recipe.RecipeDTO, error)
+	UpdateRecipe(ctx context.Context, dto recipe.UpdateRecipeDTO) error
+	DeleteRecipe(ctx context.Context, dto recipe.RecipeUserDTO) error
+	AddToList(ctx context.Context, dto recipe.AddToListDTO) (recipe.AddedRecipeDTO, error)
+}
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewRecipeHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "recipe_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	recipesRouter := router.Group("groups", mw.AuthMiddleware(authService))
+	{
+		recipesRouter.POST("/:group_id/recipes", h.CreateRecipe)
+		recipesRouter.GET("/:group_id/recipes", mw.LocaleMiddleware(authService), h.GetRecipes)
+		recipesRouter.GET("/:group_id/recipes/:recipe_id", mw.LocaleMiddleware(authService), h.GetRecipe)
+		recipesRouter.PATCH("/:group_id/recipes/:recipe_id", h.UpdateRecipe)
+		recipesRouter.DELETE("/:group_id/recipes/:recipe_id", h.DeleteRecipe)
+		recipesRouter.POST("/:group_id/recipes/:recipe_id/add-to-list", h.AddToList)
+	}
+}
+
+func toIngredientDTOs(requests []IngredientRequest) []recipe.NewIngredientDTO {
+	if requests == nil {
+		return nil
+	}
+
+	ingredients := make([]recipe.NewIngredientDTO, 0, len(requests))
+	for _, request := range requests {
+		ingredients = append(ingredients, recipe.NewIngredientDTO{
+			ProductNameID: request.ProductNameID,
+			Quantity:      request.Quantity,
+			Unit:          request.Unit,
+			Note:          request.Note,
+		})
+	}
+
+	return ingredients
+}
+
+// @Security		ApiKeyAuth
+// @Summary		CreateRecipe
+// @Description	save a recipe of the group, the quantities of the ingredients are for the servings of the recipe
+// @Tags			recipes
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		CreateRecipeRequest	true	"name, servings and ingredients"
+// @Success		200		{object}	RecipeResponse
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/recipes [post]
+func (h *Handler) CreateRecipe(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request CreateRecipeRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	recipeID, err := h.service.CreateRecipe(c.Request.Context(), recipe.CreateRecipeDTO{
+		GroupID:     groupID,
+		UserID:      userID.(uint64),
+		Name:        request.Name,
+		Servings:    request.Servings,
+		Ingredients: toIngredientDTOs(request.Ingredients),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrInvalidUnit) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing CreateRecipe", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, RecipeResponse{RecipeID: recipeID})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetRecipes
+// @Description	get the recipes of the group with their ingredients
+// @Tags			recipes
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
+// @Success		200		{object}	recipe.RecipeDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/recipes [get]
+func (h *Handler) GetRecipes(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	recipes, err := h.service.GetRecipes(c.Request.Context(), recipe.GroupRecipesDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		Locales: c.GetStringSlice("locales"),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetRecipes", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, recipes)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetRecipe
+// @Description	get a recipe of the group with its ingredients
+// @Tags			recipes
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			recipe_id	path		string	true	"Recipe ID"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
+// @Success		200		{object}	recipe.RecipeDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/recipes/{recipe_id} [get]
+func (h *Handler) GetRecipe(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	recipeID, err := strconv.ParseUint(c.Param("recipe_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':recipe_id' is not correct", nil))
+		return
+	}
+
+	rcp, err := h.service.GetRecipe(c.Request.Context(), recipe.RecipeUserDTO{
+		GroupID:  groupID,
+		UserID:   userID.(uint64),
+		RecipeID: recipeID,
+		Locales:  c.GetStringSlice("locales"),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrRecipeNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetRecipe", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, rcp)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateRecipe
+// @Description	rename a recipe, change its servings or replace its ingredients
+// @Tags			recipes
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			recipe_id	path		string	true	"Recipe ID"
+// @Param			input	body		UpdateRecipeRequest	true	"fields to change"
+// @Success		200		{object}	response.APIResponse
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/recipes/{recipe_id} [patch]
+func (h *Handler) UpdateRecipe(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	recipeID, err := strconv.ParseUint(c.Param("recipe_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':recipe_id' is not correct", nil))
+		return
+	}
+
+	var request UpdateRecipeRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err = h.service.UpdateRecipe(c.Request.Context(), recipe.UpdateRecipeDTO{
+		GroupID:     groupID,
+		UserID:      userID.(uint64),
+		RecipeID:    recipeID,
+		Name:        request.Name,
+		Servings:    request.Servings,
+		Ingredients: toIngredientDTOs(request.Ingredients),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrRecipeNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrInvalidUnit) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing UpdateRecipe", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		DeleteRecipe
+// @Description	delete a recipe of the group
+// @Tags			recipes
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			recipe_id	path		string	true	"Recipe ID"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/recipes/{recipe_id} [delete]
+func (h *Handler) DeleteRecipe(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	recipeID, err := strconv.ParseUint(c.Param("recipe_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':recipe_id' is not correct", nil))
+		return
+	}
+
+	err = h.service.DeleteRecipe(c.Request.Context(), recipe.RecipeUserDTO{
+		GroupID:  groupID,
+		UserID:   userID.(uint64),
+		RecipeID: recipeID,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrRecipeNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing DeleteRecipe", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		AddToList
+// @Description	add the ingredients scaled to the servings to the list, without what the pantry has, merging them with the open products
+// @Tags			recipes
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			recipe_id	path		string	true	"Recipe ID"
+// @Param			servings	query		int	false	"servings to buy for, the servings of the recipe by default"
+// @Success		200		{object}	AddToListResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/recipes/{recipe_id}/add-to-list [post]
+func (h *Handler) AddToList(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	recipeID, err := strconv.ParseUint(c.Param("recipe_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':recipe_id' is not correct", nil))
+		return
+	}
+
+	var request AddToListRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	added, err := h.service.AddToList(c.Request.Context(), recipe.AddToListDTO{
+		GroupID:  groupID,
+		UserID:   userID.(uint64),
+		RecipeID: recipeID,
+		Servings: request.Servings,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrRecipeNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing AddToList", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	products := make([]ProductResponse, 0, len(added.Products))
+	for _, product := range added.Products {
+		products = append(products, ProductResponse{
+			ProductID:      product.ProductID,
+			EstimatedPrice: product.EstimatedPrice,
+			Currency:       product.Currency,
+		})
+	}
+
+	c.JSON(http.StatusOK, AddToListResponse{
+		Products: products,
+		InPantry: added.InPantry,
+	})
+}
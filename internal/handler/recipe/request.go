@@ -0,0 +1,26 @@
+package recipe
+
+type IngredientRequest struct {
+	ProductNameID uint64  `json:"product_name_id" binding:"required"`
+	Quantity      float64 `json:"quantity" binding:"required,gt=0,max=100000"`
+	Unit          string  `json:"unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
+	Note          string  `json:"note" binding:"max=500"`
+}
+
+type CreateRecipeRequest struct {
+	Name        string              `json:"name" binding:"required,min=1,max=100"`
+	Servings    int                 `json:"servings" binding:"required,min=1,max=100"`
+	Ingredients []IngredientRequest `json:"ingredients" binding:"required,min=1,max=200,dive"`
+}
+
+// UpdateRecipeRequest changes only the given fields, the ingredients are replaced when they are passed
+type UpdateRecipeRequest struct {
+	Name        string              `json:"name" binding:"omitempty,max=100"`
+	Servings    int                 `json:"servings" binding:"omitempty,min=1,max=100"`
+	Ingredients []IngredientRequest `json:"ingredients" binding:"omitempty,min=1,max=200,dive"`
+}
+
+type AddToListRequest struct {
+	// Servings to buy for, the servings of the recipe when omitted
+	Servings int `form:"servings" binding:"omitempty,min=1,max=100"`
+}
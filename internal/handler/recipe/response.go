@@ -0,0 +1,19 @@
+package recipe
+
+import "github.com/shopspring/decimal"
+
+type RecipeResponse struct {
+	RecipeID uint64 `json:"recipe_id"`
+}
+
+type ProductResponse struct {
+	ProductID      uint64           `json:"product_id"`
+	EstimatedPrice *decimal.Decimal `json:"estimated_price"`
+	Currency       string           `json:"currency"`
+}
+
+// AddToListResponse lists the added or merged products, in_pantry are the product names the pantry has enough of
+type AddToListResponse struct {
+	Products []ProductResponse `json:"products"`
+	InPantry []uint64          `json:"in_pantry_product_name_ids"`
+}
@@ -0,0 +1,21 @@
+package recurring
+
+// CreateRuleRequest needs exactly one schedule: a cron expression in UTC like "0 9 * * 1"
+// or the number of days after the product is bought
+type CreateRuleRequest struct {
+	ProductNameID uint64  `json:"product_name_id" binding:"required"`
+	Quantity      float64 `json:"quantity" binding:"required,gt=0,max=100000"`
+	Unit          string  `json:"unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
+	Note          string  `json:"note" binding:"max=500"`
+	Cron          string  `json:"cron" binding:"required_without=EveryDays,excluded_with=EveryDays,max=100"`
+	EveryDays     int     `json:"every_days" binding:"required_without=Cron,excluded_with=Cron,omitempty,min=1,max=365"`
+}
+
+// UpdateRuleRequest changes only the given fields, a cron or every_days replaces the schedule
+type UpdateRuleRequest struct {
+	Quantity  float64 `json:"quantity" binding:"omitempty,gt=0,max=100000"`
+	Unit      string  `json:"unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
+	Note      *string `json:"note" binding:"omitempty,max=500"`
+	Cron      string  `json:"cron" binding:"excluded_with=EveryDays,max=100"`
+	EveryDays int     `json:"every_days" binding:"excluded_with=Cron,omitempty,min=1,max=365"`
+}
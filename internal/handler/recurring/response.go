@@ -0,0 +1,5 @@
+package recurring
+
+type RuleResponse struct {
+	RecurringProductID uint64 `json:"recurring_product_id"`
+}
@@ -2,18 +2,37 @@ package handler
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "github.com/tclutin/shoppinglist-api/docs"
 	"github.com/tclutin/shoppinglist-api/internal/config"
 	"github.com/tclutin/shoppinglist-api/internal/domain"
+	"github.com/tclutin/shoppinglist-api/internal/handler/admin"
 	"github.com/tclutin/shoppinglist-api/internal/handler/auth"
+	"github.com/tclutin/shoppinglist-api/internal/handler/barcode"
+	"github.com/tclutin/shoppinglist-api/internal/handler/budget"
+	"github.com/tclutin/shoppinglist-api/internal/handler/currency"
+	"github.com/tclutin/shoppinglist-api/internal/handler/expense"
 	"github.com/tclutin/shoppinglist-api/internal/handler/group"
+	"github.com/tclutin/shoppinglist-api/internal/handler/mealplan"
 	"github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/internal/handler/pantry"
+	"github.com/tclutin/shoppinglist-api/internal/handler/price"
 	"github.com/tclutin/shoppinglist-api/internal/handler/product"
+	"github.com/tclutin/shoppinglist-api/internal/handler/recipe"
+	"github.com/tclutin/shoppinglist-api/internal/handler/recurring"
+	"github.com/tclutin/shoppinglist-api/internal/handler/stats"
+	"github.com/tclutin/shoppinglist-api/internal/handler/store"
+	"github.com/tclutin/shoppinglist-api/internal/handler/suggestion"
+	"github.com/tclutin/shoppinglist-api/internal/handler/template"
+	"github.com/tclutin/shoppinglist-api/internal/handler/trip"
 	"github.com/tclutin/shoppinglist-api/internal/handler/user"
 	"github.com/tclutin/shoppinglist-api/pkg/logger"
 	"net/http"
+	"reflect"
 )
 
 func NewRouter(cfg *config.Config, logger logger.Logger, services *domain.Services) *gin.Engine {
@@ -25,6 +44,11 @@ func NewRouter(cfg *config.Config, logger logger.Logger, services *domain.Servic
 		gin.SetMode(gin.DebugMode)
 	}
 
+	// prices and amounts stay numbers in JSON
+	decimal.MarshalJSONWithoutQuotes = true
+
+	registerValidators()
+
 	router := gin.Default()
 
 	router.Use(middleware.CORSMiddleware())
@@ -41,7 +65,38 @@ func NewRouter(cfg *config.Config, logger logger.Logger, services *domain.Servic
 		user.NewGroupHandler(logger, services.User).Init(root, services.Auth)
 		group.NewGroupHandler(logger, services.Group).Init(root, services.Auth)
 		product.NewGroupHandler(logger, services.Product).Init(root, services.Auth)
+		admin.NewAdminHandler(logger, services.Product).Init(root, services.Auth)
+		trip.NewTripHandler(logger, services.Trip).Init(root, services.Auth)
+		expense.NewExpenseHandler(logger, services.Expense).Init(root, services.Auth)
+		stats.NewStatsHandler(logger, services.Stats).Init(root, services.Auth)
+		price.NewPriceHandler(logger, services.Price).Init(root, services.Auth)
+		budget.NewBudgetHandler(logger, services.Budget).Init(root, services.Auth)
+		currency.NewCurrencyHandler(logger, services.Currency).Init(root, services.Auth)
+		recurring.NewRecurringHandler(logger, services.Recurring).Init(root, services.Auth)
+		template.NewTemplateHandler(logger, services.Template).Init(root, services.Auth)
+		suggestion.NewSuggestionHandler(logger, services.Suggestion).Init(root, services.Auth)
+		pantry.NewPantryHandler(logger, services.Pantry).Init(root, services.Auth)
+		recipe.NewRecipeHandler(logger, services.Recipe).Init(root, services.Auth)
+		mealplan.NewMealPlanHandler(logger, services.MealPlan).Init(root, services.Auth)
+		store.NewStoreHandler(logger, services.Store).Init(root, services.Auth)
+		barcode.NewBarcodeHandler(logger, services.Barcode).Init(root, services.Auth)
 	}
 
 	return router
 }
+
+// registerValidators lets the binding tags like gt and max check decimal prices and amounts
+func registerValidators() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		if value, ok := field.Interface().(decimal.Decimal); ok {
+			return value.InexactFloat64()
+		}
+
+		return nil
+	}, decimal.Decimal{})
+}
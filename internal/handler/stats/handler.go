@@ -0,0 +1,264 @@
+package stats
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/stats"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type Service interface {
+	GetStats(ctx context.Context, dto stats.PeriodDTO) (stats.StatsDTO, error)
+	GetItems(ctx context.Context, dto stats.PeriodDTO) ([]stats.ItemDTO, error)
+}
+
+const defaultTopItems = 10
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewStatsHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "stats_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	statsRouter := router.Group("groups", mw.AuthMiddleware(authService), mw.LocaleMiddleware(authService))
+	{
+		statsRouter.GET("/:group_id/stats", h.GetStats)
+		statsRouter.GET("/:group_id/stats/export", h.Export)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetStats
+// @Description	get spending of group by category, member and month, average prices and most bought products over a period of purchase dates
+// @Tags			stats
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			from	query		string	false	"first day of period, e.g. 2025-02-01"
+// @Param			to	query		string	false	"last day of period, e.g. 2025-02-28"
+// @Param			top	query		int	false	"number of most bought products, 10 by default"
+// @Param			Accept-Language	header		string	false	"preferred languages of product and category names"
+// @Success		200		{object}	stats.StatsDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/stats [get]
+func (h *Handler) GetStats(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request GetStatsRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	if request.Top == 0 {
+		request.Top = defaultTopItems
+	}
+
+	from, to := parsePeriod(request.From, request.To)
+
+	groupStats, err := h.service.GetStats(c.Request.Context(), stats.PeriodDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		Locales: c.GetStringSlice("locales"),
+		From:    from,
+		To:      to,
+		Top:     request.Top,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetStats", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, groupStats)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		Export
+// @Description	export products bought by group over a period of purchase dates as CSV
+// @Tags			stats
+// @Accept			json
+// @Produce		text/csv
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			from	query		string	false	"first day of period, e.g. 2025-02-01"
+// @Param			to	query		string	false	"last day of period, e.g. 2025-02-28"
+// @Param			Accept-Language	header		string	false	"preferred languages of product and category names"
+// @Success		200		{string}	string
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/stats/export [get]
+func (h *Handler) Export(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request ExportRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	from, to := parsePeriod(request.From, request.To)
+
+	items, err := h.service.GetItems(c.Request.Context(), stats.PeriodDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		Locales: c.GetStringSlice("locales"),
+		From:    from,
+		To:      to,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing Export", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"group-%d-products.csv\"", groupID))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+
+	_ = writer.Write([]string{"product_id", "bought_at", "product", "category", "quantity", "unit", "price", "currency", "group_price", "bought_by", "trip_id", "store"})
+	for _, item := range items {
+		_ = writer.Write(itemRecord(item))
+	}
+
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		h.logger.Error("error occurred while writing export", slog.Any("error", err))
+	}
+}
+
+func itemRecord(item stats.ItemDTO) []string {
+	record := []string{
+		strconv.FormatUint(item.ProductID, 10),
+		item.BoughtAt.Format("2006-01-02 15:04:05"),
+		escapeCell(item.ProductName),
+		escapeCell(item.CategoryName),
+		strconv.FormatFloat(item.Quantity, 'f', -1, 64),
+		item.Unit,
+		"",
+		item.Currency,
+		"",
+		"",
+		"",
+		"",
+	}
+
+	if item.Price != nil {
+		record[6] = item.Price.StringFixed(2)
+	}
+
+	if item.GroupPrice != nil {
+		record[8] = item.GroupPrice.StringFixed(2)
+	}
+
+	if item.BoughtBy != nil {
+		record[9] = escapeCell(*item.BoughtBy)
+	}
+
+	if item.TripID != nil {
+		record[10] = strconv.FormatUint(*item.TripID, 10)
+	}
+
+	if item.Store != nil {
+		record[11] = escapeCell(*item.Store)
+	}
+
+	return record
+}
+
+// escapeCell keeps a spreadsheet from running a name typed by a user as a formula
+func escapeCell(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+
+	return text
+}
@@ -0,0 +1,33 @@
+package stats
+
+import "time"
+
+// dateLayout is the format of dates in requests, e.g. "2025-02-01"
+const dateLayout = "2006-01-02"
+
+type GetStatsRequest struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	Top  int    `form:"top" binding:"omitempty,min=1,max=100"`
+}
+
+type ExportRequest struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// parsePeriod parses already validated dates, the period includes the whole "to" day
+func parsePeriod(from, to string) (*time.Time, *time.Time) {
+	var fromDate, toDate *time.Time
+
+	if date, err := time.Parse(dateLayout, from); err == nil {
+		fromDate = &date
+	}
+
+	if date, err := time.Parse(dateLayout, to); err == nil {
+		nextDay := date.AddDate(0, 0, 1)
+		toDate = &nextDay
+	}
+
+	return fromDate, toDate
+}
@@ -0,0 +1,18 @@
+package store
+
+type SectionRequest struct {
+	Name        string   `json:"name" binding:"required,min=1,max=100"`
+	CategoryIDs []uint64 `json:"category_ids" binding:"max=100"`
+}
+
+// CreateStoreRequest lists the sections in the order they are walked through
+type CreateStoreRequest struct {
+	Name     string           `json:"name" binding:"required,min=1,max=100"`
+	Sections []SectionRequest `json:"sections" binding:"required,min=1,max=100,dive"`
+}
+
+// UpdateStoreRequest replaces the sections when they are passed
+type UpdateStoreRequest struct {
+	Name     string           `json:"name" binding:"omitempty,max=100"`
+	Sections []SectionRequest `json:"sections" binding:"omitempty,min=1,max=100,dive"`
+}
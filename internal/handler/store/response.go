@@ -0,0 +1,5 @@
+package store
+
+type StoreResponse struct {
+	StoreID uint64 `json:"store_id"`
+}
@@ -0,0 +1,23 @@
+package template
+
+type TemplateItemRequest struct {
+	ProductNameID uint64  `json:"product_name_id" binding:"required"`
+	Quantity      float64 `json:"quantity" binding:"required,gt=0,max=100000"`
+	Unit          string  `json:"unit" binding:"omitempty,oneof=pcs g kg ml l pack"`
+	Note          string  `json:"note" binding:"max=500"`
+}
+
+type CreateTemplateRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+	// Personal templates belong to the user and can be applied in any of their groups
+	Personal bool `json:"personal"`
+	// FromOpenProducts copies the open products of the group, items are ignored then
+	FromOpenProducts bool                  `json:"from_open_products"`
+	Items            []TemplateItemRequest `json:"items" binding:"required_without=FromOpenProducts,max=200,dive"`
+}
+
+// UpdateTemplateRequest replaces the items when they are passed
+type UpdateTemplateRequest struct {
+	Name  string                `json:"name" binding:"omitempty,max=100"`
+	Items []TemplateItemRequest `json:"items" binding:"omitempty,min=1,max=200,dive"`
+}
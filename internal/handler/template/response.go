@@ -0,0 +1,19 @@
+package template
+
+import "github.com/shopspring/decimal"
+
+type TemplateResponse struct {
+	TemplateID uint64 `json:"template_id"`
+}
+
+type ProductResponse struct {
+	ProductID      uint64           `json:"product_id"`
+	EstimatedPrice *decimal.Decimal `json:"estimated_price"`
+	Currency       string           `json:"currency"`
+}
+
+// ApplyResponse lists the added or merged products, skipped are the product names private to another group
+type ApplyResponse struct {
+	Products []ProductResponse `json:"products"`
+	Skipped  []uint64          `json:"skipped_product_name_ids"`
+}
@@ -0,0 +1,306 @@
+package trip
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
+	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
+	"github.com/tclutin/shoppinglist-api/internal/domain/trip"
+	mw "github.com/tclutin/shoppinglist-api/internal/handler/middleware"
+	"github.com/tclutin/shoppinglist-api/pkg/logger"
+	"github.com/tclutin/shoppinglist-api/pkg/response"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Service interface {
+	CreateTrip(ctx context.Context, dto trip.CreateTripDTO) (uint64, error)
+	GetGroupTrips(ctx context.Context, dto trip.GroupTripsDTO) ([]trip.TripDTO, error)
+	GetTrip(ctx context.Context, dto trip.TripUserDTO) (trip.TripDTO, error)
+}
+
+const defaultTripsLimit = 20
+
+type Handler struct {
+	logger  logger.Logger
+	service Service
+}
+
+func NewTripHandler(logger logger.Logger, service Service) *Handler {
+	return &Handler{
+		logger:  logger.With("handler", "trip_handler"),
+		service: service,
+	}
+}
+
+func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
+	tripsRouter := router.Group("groups", mw.AuthMiddleware(authService))
+	{
+		tripsRouter.POST("/:group_id/trips", h.CreateTrip)
+		tripsRouter.GET("/:group_id/trips", mw.LocaleMiddleware(authService), h.GetGroupTrips)
+		tripsRouter.GET("/:group_id/trips/:trip_id", mw.LocaleMiddleware(authService), h.GetTrip)
+	}
+}
+
+// @Security		ApiKeyAuth
+// @Summary		CreateTrip
+// @Description	close the bought products of a shopping trip with their prices in one go
+// @Tags			trips
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			input	body		CreateTripRequest	true	"store, date, total and bought products"
+// @Success		200		{object}	TripResponse
+// @Failure		400		{object}	response.APIError
+// @Failure		401		{object}	response.APIError
+// @Failure		409		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/trips [post]
+func (h *Handler) CreateTrip(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request CreateTripRequest
+
+	if err = c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if request.Date != "" {
+		date, _ = time.Parse(time.DateOnly, request.Date)
+	}
+
+	items := make([]trip.Item, 0, len(request.Items))
+	for _, item := range request.Items {
+		items = append(items, trip.Item{
+			ProductID: item.ProductID,
+			Price:     item.Price,
+		})
+	}
+
+	tripID, err := h.service.CreateTrip(c.Request.Context(), trip.CreateTripDTO{
+		GroupID:  groupID,
+		UserID:   userID.(uint64),
+		Store:    request.Store,
+		Date:     date,
+		Total:    request.Total,
+		Currency: request.Currency,
+		Items:    items,
+	})
+
+	if err != nil {
+		// the trip is saved, only its budget alerts are missing
+		if errors.Is(err, domainErr.ErrThresholdsNotChecked) {
+			h.logger.Error("error occurred while checking budget thresholds", slog.Any("error", err))
+			c.JSON(http.StatusOK, TripResponse{TripID: tripID})
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrProductUnavailable) {
+			c.AbortWithStatusJSON(http.StatusConflict,
+				response.NewAPIError(http.StatusConflict, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrUnknownCurrency) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing CreateTrip", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, TripResponse{TripID: tripID})
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetGroupTrips
+// @Description	get shopping trips of group with their products, the latest first
+// @Tags			trips
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			limit	query		int	false	"page size, 20 by default"
+// @Param			offset	query		int	false	"page offset"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
+// @Success		200		{object}	trip.TripDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/trips [get]
+func (h *Handler) GetGroupTrips(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	var request GetTripsRequest
+
+	if err = c.ShouldBindQuery(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	if request.Limit == 0 {
+		request.Limit = defaultTripsLimit
+	}
+
+	trips, err := h.service.GetGroupTrips(c.Request.Context(), trip.GroupTripsDTO{
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		Locales: c.GetStringSlice("locales"),
+		Limit:   request.Limit,
+		Offset:  request.Offset,
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetGroupTrips", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, trips)
+}
+
+// @Security		ApiKeyAuth
+// @Summary		GetTrip
+// @Description	get a shopping trip with its products
+// @Tags			trips
+// @Accept			json
+// @Produce		json
+// @Param			group_id	path		string	true	"Group ID"
+// @Param			trip_id	path		string	true	"Trip ID"
+// @Param			Accept-Language	header		string	false	"preferred languages of product names"
+// @Success		200		{object}	trip.TripDTO
+// @Failure		401		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/groups/{group_id}/trips/{trip_id} [get]
+func (h *Handler) GetTrip(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':group_id' is not correct", nil))
+		return
+	}
+
+	tripID, err := strconv.ParseUint(c.Param("trip_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, "':trip_id' is not correct", nil))
+		return
+	}
+
+	trp, err := h.service.GetTrip(c.Request.Context(), trip.TripUserDTO{
+		TripID:  tripID,
+		GroupID: groupID,
+		UserID:  userID.(uint64),
+		Locales: c.GetStringSlice("locales"),
+	})
+
+	if err != nil {
+		if errors.Is(err, domainErr.ErrGroupNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrMemberNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrTripNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing GetTrip", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, trp)
+}
@@ -0,0 +1,23 @@
+package trip
+
+import "github.com/shopspring/decimal"
+
+type CreateTripRequest struct {
+	Store string `json:"store" binding:"max=100"`
+	// Date is today when omitted
+	Date  string           `json:"date" binding:"omitempty,datetime=2006-01-02"`
+	Total *decimal.Decimal `json:"total" binding:"omitempty,gte=0"`
+	// Currency of the total and the item prices, the currency of the group when omitted
+	Currency string            `json:"currency" binding:"omitempty,iso4217"`
+	Items    []TripItemRequest `json:"items" binding:"required,min=1,max=100,unique=ProductID,dive"`
+}
+
+type TripItemRequest struct {
+	ProductID uint64           `json:"product_id" binding:"required"`
+	Price     *decimal.Decimal `json:"price" binding:"omitempty,gte=0"`
+}
+
+type GetTripsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,5 @@
+package trip
+
+type TripResponse struct {
+	TripID uint64 `json:"trip_id"`
+}
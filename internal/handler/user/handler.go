@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/tclutin/shoppinglist-api/internal/domain/auth"
 	domainErr "github.com/tclutin/shoppinglist-api/internal/domain/errors"
@@ -15,6 +16,7 @@ import (
 
 type Service interface {
 	GetGroupsByUserId(ctx context.Context, userId uint64) ([]group.GroupDTO, error)
+	UpdateLocale(ctx context.Context, userID uint64, locale string) error
 }
 
 type Handler struct {
@@ -33,6 +35,7 @@ func (h *Handler) Init(router *gin.RouterGroup, authService *auth.Service) {
 	usersRouter := router.Group("users", mw.AuthMiddleware(authService))
 	{
 		usersRouter.GET("/groups", h.GetUserGroups)
+		usersRouter.PATCH("/locale", h.UpdateLocale)
 	}
 }
 
@@ -66,3 +69,59 @@ func (h *Handler) GetUserGroups(c *gin.Context) {
 
 	c.JSON(http.StatusOK, groups)
 }
+
+// @Security		ApiKeyAuth
+// @Summary		UpdateLocale
+// @Description	set the language of the catalog used when Accept-Language has no known locale
+// @Tags			users
+// @Accept			json
+// @Produce		json
+// @Param			input	body		UpdateLocaleRequest	true	"profile locale"
+// @Success		200		{object}	response.APIResponse
+// @Failure		401		{object}	response.APIError
+// @Failure		400		{object}	response.APIError
+// @Failure		404		{object}	response.APIError
+// @Failure		422		{object}	response.APIError
+// @Failure		500		{object}	response.APIError
+// @Router			/users/locale [PATCH]
+func (h *Handler) UpdateLocale(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.AbortWithStatusJSON(
+			http.StatusUnauthorized,
+			response.NewAPIError(http.StatusUnauthorized, domainErr.ErrMissingCredentials.Error(), nil))
+		return
+	}
+
+	var request UpdateLocaleRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(
+			http.StatusUnprocessableEntity,
+			response.NewAPIError(http.StatusUnprocessableEntity, err.Error(), nil))
+		return
+	}
+
+	err := h.service.UpdateLocale(c.Request.Context(), userID.(uint64), request.Locale)
+	if err != nil {
+		if errors.Is(err, domainErr.ErrUserNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				response.NewAPIError(http.StatusNotFound, err.Error(), nil))
+			return
+		}
+
+		if errors.Is(err, domainErr.ErrInvalidLocale) {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				response.NewAPIError(http.StatusBadRequest, err.Error(), nil))
+			return
+		}
+
+		h.logger.Error("error occurred while processing UpdateLocale", slog.Any("error", err))
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			response.NewAPIError(http.StatusInternalServerError, "Internal server error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse{Message: "success"})
+}
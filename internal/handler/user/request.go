@@ -0,0 +1,5 @@
+package user
+
+type UpdateLocaleRequest struct {
+	Locale string `json:"locale" binding:"required,min=2,max=10"`
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/barcode"
+)
+
+type BarcodeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBarcodeRepository(db *pgxpool.Pool) *BarcodeRepository {
+	return &BarcodeRepository{db: db}
+}
+
+// Lookup prefers the barcodes learned by the groups of the user, or by groupID when it's not zero,
+// the latest one first, over the global barcode
+func (b *BarcodeRepository) Lookup(ctx context.Context, userID uint64, groupID uint64, code string, locales []string) (barcode.BarcodeDTO, error) {
+	sql := `WITH user_groups AS (
+				SELECT group_id FROM public.members WHERE user_id = $1 AND ($2 = 0 OR group_id = $2)
+			)
+			SELECT b.code,
+				   pn.product_name_id,
+				   COALESCE(pnt.name, pn.name) as product_name,
+				   c.category_id,
+				   COALESCE(ct.name, c.name) as category_name,
+				   pn.default_unit,
+				   b.group_id
+			FROM public.barcodes as b
+			INNER JOIN public.product_names as pn
+				ON pn.product_name_id = b.product_name_id
+			INNER JOIN public.categories as c
+				ON c.category_id = pn.category_id
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.product_name_translations as t
+				WHERE t.product_name_id = pn.product_name_id AND t.locale = ANY($4::text[])
+				ORDER BY array_position($4::text[], t.locale)
+				LIMIT 1
+			) as pnt ON true
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.category_translations as t
+				WHERE t.category_id = c.category_id AND t.locale = ANY($4::text[])
+				ORDER BY array_position($4::text[], t.locale)
+				LIMIT 1
+			) as ct ON true
+			WHERE b.code = $3 AND (b.group_id IS NULL OR b.group_id IN (SELECT group_id FROM user_groups))
+			ORDER BY b.group_id IS NULL, b.created_at DESC
+			LIMIT 1`
+
+	rows, err := b.db.Query(ctx, sql, userID, groupID, code, locales)
+	if err != nil {
+		return barcode.BarcodeDTO{}, err
+	}
+
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[barcode.BarcodeDTO])
+}
+
+func (b *BarcodeRepository) Learn(ctx context.Context, bc barcode.Barcode) (uint64, error) {
+	sql := `INSERT INTO public.barcodes (code, product_name_id, group_id, created_by, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (group_id, code) WHERE group_id IS NOT NULL
+			DO UPDATE SET product_name_id = EXCLUDED.product_name_id,
+						  created_by = EXCLUDED.created_by,
+						  created_at = EXCLUDED.created_at
+			RETURNING barcode_id`
+
+	row := b.db.QueryRow(ctx, sql, bc.Code, bc.ProductNameID, bc.GroupID, bc.CreatedBy, bc.CreatedAt)
+
+	var barcodeID uint64
+	if err := row.Scan(&barcodeID); err != nil {
+		return 0, err
+	}
+
+	return barcodeID, nil
+}
+
+// ImportGlobal adds or replaces the global barcodes whose product names are global, the codes
+// must be unique, it returns the codes it saved
+func (b *BarcodeRepository) ImportGlobal(ctx context.Context, mappings []barcode.Mapping) ([]string, error) {
+	codes := make([]string, 0, len(mappings))
+	productNameIDs := make([]uint64, 0, len(mappings))
+
+	for _, mapping := range mappings {
+		codes = append(codes, mapping.Code)
+		productNameIDs = append(productNameIDs, mapping.ProductNameID)
+	}
+
+	sql := `INSERT INTO public.barcodes (code, product_name_id)
+			SELECT m.code, m.product_name_id
+			FROM unnest($1::text[], $2::bigint[]) as m(code, product_name_id)
+			INNER JOIN public.product_names as pn
+				ON pn.product_name_id = m.product_name_id AND pn.group_id IS NULL
+			ON CONFLICT (code) WHERE group_id IS NULL
+			DO UPDATE SET product_name_id = EXCLUDED.product_name_id,
+						  created_at = current_timestamp
+			RETURNING code`
+
+	rows, err := b.db.Query(ctx, sql, codes, productNameIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
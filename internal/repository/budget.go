@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/budget"
+	"time"
+)
+
+type BudgetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBudgetRepository(db *pgxpool.Pool) *BudgetRepository {
+	return &BudgetRepository{db: db}
+}
+
+// Create saves the budget, pgx.ErrNoRows means the group has a budget for the category already
+func (b *BudgetRepository) Create(ctx context.Context, budget budget.Budget) (uint64, error) {
+	sql := `INSERT INTO public.budgets (group_id, category_id, amount, created_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (group_id, COALESCE(category_id, 0)) DO NOTHING
+			RETURNING budget_id`
+
+	row := b.db.QueryRow(ctx, sql, budget.GroupID, budget.CategoryID, budget.Amount, budget.CreatedAt)
+
+	var budgetID uint64
+	if err := row.Scan(&budgetID); err != nil {
+		return 0, err
+	}
+
+	return budgetID, nil
+}
+
+func (b *BudgetRepository) GetById(ctx context.Context, budgetID uint64) (budget.Budget, error) {
+	sql := `SELECT budget_id, group_id, category_id, amount, created_at FROM public.budgets WHERE budget_id = $1`
+
+	row := b.db.QueryRow(ctx, sql, budgetID)
+
+	var bdgt budget.Budget
+	err := row.Scan(
+		&bdgt.BudgetID,
+		&bdgt.GroupID,
+		&bdgt.CategoryID,
+		&bdgt.Amount,
+		&bdgt.CreatedAt)
+
+	if err != nil {
+		return bdgt, err
+	}
+
+	return bdgt, nil
+}
+
+func (b *BudgetRepository) Update(ctx context.Context, budget budget.Budget) error {
+	sql := `UPDATE public.budgets SET amount = $1 WHERE budget_id = $2`
+
+	_, err := b.db.Exec(ctx, sql, budget.Amount, budget.BudgetID)
+
+	return err
+}
+
+func (b *BudgetRepository) Delete(ctx context.Context, budgetID uint64) error {
+	sql := `DELETE FROM public.budgets WHERE budget_id = $1`
+
+	_, err := b.db.Exec(ctx, sql, budgetID)
+
+	return err
+}
+
+// GetBudgets returns the budgets of the group with the prices of the products bought in [from, to)
+// converted to the currency of the group, the budget of the whole group first
+func (b *BudgetRepository) GetBudgets(ctx context.Context, groupID uint64, locales []string, from time.Time, to time.Time) ([]budget.BudgetDTO, error) {
+	sql := `SELECT b.budget_id,
+				   b.category_id,
+				   COALESCE(ct.name, c.name) as category_name,
+				   b.amount,
+				   COALESCE(spent.total, 0) as spent,
+				   g.currency
+			FROM public.budgets as b
+			INNER JOIN public.groups as g
+				ON g.group_id = b.group_id
+			INNER JOIN public.exchange_rates as gr
+				ON gr.currency = g.currency
+			LEFT JOIN public.categories as c
+				ON c.category_id = b.category_id
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.category_translations as t
+				WHERE t.category_id = c.category_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as ct ON true
+			LEFT JOIN LATERAL (
+				SELECT ROUND(SUM(p.price * pr.rate) / gr.rate, 2) as total
+				FROM public.products as p
+				INNER JOIN public.exchange_rates as pr
+					ON pr.currency = COALESCE(p.currency, g.currency)
+				INNER JOIN public.product_names as pn
+					ON pn.product_name_id = p.product_name_id
+				WHERE p.group_id = b.group_id
+				  AND p.status = 'closed'
+				  AND p.price IS NOT NULL
+				  AND p.bought_at >= $3 AND p.bought_at < $4
+				  AND (b.category_id IS NULL OR pn.category_id = b.category_id)
+			) as spent ON true
+			WHERE b.group_id = $1
+			ORDER BY b.category_id NULLS FIRST`
+
+	rows, err := b.db.Query(ctx, sql, groupID, locales, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[budget.BudgetDTO])
+}
+
+// CreateAlert saves the alert unless the threshold of the budget has alerted in the period already
+func (b *BudgetRepository) CreateAlert(ctx context.Context, alert budget.Alert) error {
+	sql := `INSERT INTO public.budget_alerts (budget_id, period_start, threshold, spent, amount, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (budget_id, period_start, threshold) DO NOTHING`
+
+	_, err := b.db.Exec(ctx, sql,
+		alert.BudgetID,
+		alert.PeriodStart,
+		alert.Threshold,
+		alert.Spent,
+		alert.Amount,
+		alert.CreatedAt)
+
+	return err
+}
+
+func (b *BudgetRepository) GetAlerts(ctx context.Context, groupID uint64, locales []string, limit int, offset int) ([]budget.AlertDTO, error) {
+	sql := `SELECT a.budget_alert_id,
+				   a.budget_id,
+				   b.category_id,
+				   COALESCE(ct.name, c.name) as category_name,
+				   a.period_start,
+				   a.threshold,
+				   a.spent,
+				   a.amount,
+				   a.created_at
+			FROM public.budget_alerts as a
+			INNER JOIN public.budgets as b
+				ON b.budget_id = a.budget_id
+			LEFT JOIN public.categories as c
+				ON c.category_id = b.category_id
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.category_translations as t
+				WHERE t.category_id = c.category_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as ct ON true
+			WHERE b.group_id = $1
+			ORDER BY a.created_at DESC, a.budget_alert_id DESC
+			LIMIT $3 OFFSET $4`
+
+	rows, err := b.db.Query(ctx, sql, groupID, locales, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[budget.AlertDTO])
+}
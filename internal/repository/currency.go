@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/currency"
+)
+
+type CurrencyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCurrencyRepository(db *pgxpool.Pool) *CurrencyRepository {
+	return &CurrencyRepository{db: db}
+}
+
+func (c *CurrencyRepository) GetRates(ctx context.Context) ([]currency.Rate, error) {
+	sql := `SELECT currency, rate, updated_at FROM public.exchange_rates ORDER BY currency`
+
+	rows, err := c.db.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[currency.Rate])
+}
+
+func (c *CurrencyRepository) GetRate(ctx context.Context, code string) (currency.Rate, error) {
+	sql := `SELECT currency, rate, updated_at FROM public.exchange_rates WHERE currency = $1`
+
+	rows, err := c.db.Query(ctx, sql, code)
+	if err != nil {
+		return currency.Rate{}, err
+	}
+
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[currency.Rate])
+}
+
+func (c *CurrencyRepository) SetRate(ctx context.Context, rate currency.Rate) error {
+	sql := `INSERT INTO public.exchange_rates (currency, rate, updated_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at`
+
+	_, err := c.db.Exec(ctx, sql, rate.Currency, rate.Rate, rate.UpdatedAt)
+
+	return err
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/expense"
+)
+
+type ExpenseRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewExpenseRepository(db *pgxpool.Pool) *ExpenseRepository {
+	return &ExpenseRepository{db: db}
+}
+
+// GetParticipants returns the members of the group and the former members who paid
+// for something or took part in a settlement
+func (e *ExpenseRepository) GetParticipants(ctx context.Context, groupID uint64) ([]expense.Participant, error) {
+	sql := `SELECT u.user_id, u.username, COALESCE(m.share, 0), m.member_id IS NOT NULL
+			FROM public.users as u
+			LEFT JOIN public.members as m
+				ON m.user_id = u.user_id AND m.group_id = $1
+			WHERE m.member_id IS NOT NULL OR u.user_id IN (
+				SELECT bought_by FROM public.products WHERE group_id = $1 AND status = 'closed' AND price IS NOT NULL
+				UNION SELECT buyer_id FROM public.trips WHERE group_id = $1
+				UNION SELECT from_user_id FROM public.settlements WHERE group_id = $1
+				UNION SELECT to_user_id FROM public.settlements WHERE group_id = $1
+			)
+			ORDER BY u.user_id`
+
+	rows, err := e.db.Query(ctx, sql, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []expense.Participant
+	for rows.Next() {
+		var participant expense.Participant
+		err = rows.Scan(
+			&participant.UserID,
+			&participant.Username,
+			&participant.Share,
+			&participant.IsMember)
+
+		if err != nil {
+			return nil, err
+		}
+
+		participants = append(participants, participant)
+	}
+
+	return participants, rows.Err()
+}
+
+// GetExpenses returns the prices of the bought products and the parts of the trip
+// totals that aren't covered by the prices of their products, converted to the currency of the group
+func (e *ExpenseRepository) GetExpenses(ctx context.Context, groupID uint64) ([]expense.Expense, error) {
+	sql := `SELECT p.product_id, p.bought_by, ROUND(p.price * pr.rate / gr.rate, 2)
+			FROM public.products as p
+			INNER JOIN public.groups as g
+				ON g.group_id = p.group_id
+			INNER JOIN public.exchange_rates as gr
+				ON gr.currency = g.currency
+			INNER JOIN public.exchange_rates as pr
+				ON pr.currency = COALESCE(p.currency, g.currency)
+			WHERE p.group_id = $1 AND p.status = 'closed' AND p.price IS NOT NULL AND p.bought_by IS NOT NULL
+			UNION ALL
+			SELECT NULL, t.buyer_id, ROUND((t.total * tr.rate - COALESCE(SUM(p.price * pr.rate), 0)) / gr.rate, 2)
+			FROM public.trips as t
+			INNER JOIN public.groups as g
+				ON g.group_id = t.group_id
+			INNER JOIN public.exchange_rates as gr
+				ON gr.currency = g.currency
+			INNER JOIN public.exchange_rates as tr
+				ON tr.currency = COALESCE(t.currency, g.currency)
+			LEFT JOIN public.products as p
+				ON p.trip_id = t.trip_id
+			LEFT JOIN public.exchange_rates as pr
+				ON pr.currency = COALESCE(p.currency, g.currency)
+			WHERE t.group_id = $1
+			GROUP BY t.trip_id, tr.rate, gr.rate
+			HAVING ROUND((t.total * tr.rate - COALESCE(SUM(p.price * pr.rate), 0)) / gr.rate, 2) <> 0`
+
+	rows, err := e.db.Query(ctx, sql, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expenses []expense.Expense
+	for rows.Next() {
+		var exp expense.Expense
+		if err = rows.Scan(&exp.ProductID, &exp.PayerID, &exp.Amount); err != nil {
+			return nil, err
+		}
+
+		expenses = append(expenses, exp)
+	}
+
+	return expenses, rows.Err()
+}
+
+func (e *ExpenseRepository) GetExclusions(ctx context.Context, groupID uint64) ([]expense.Exclusion, error) {
+	sql := `SELECT x.product_id, x.user_id
+			FROM public.product_split_exclusions as x
+			INNER JOIN public.products as p
+				ON p.product_id = x.product_id
+			WHERE p.group_id = $1`
+
+	rows, err := e.db.Query(ctx, sql, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exclusions []expense.Exclusion
+	for rows.Next() {
+		var exclusion expense.Exclusion
+		if err = rows.Scan(&exclusion.ProductID, &exclusion.UserID); err != nil {
+			return nil, err
+		}
+
+		exclusions = append(exclusions, exclusion)
+	}
+
+	return exclusions, rows.Err()
+}
+
+func (e *ExpenseRepository) UpdateExclusions(ctx context.Context, productID uint64, userIDs []uint64) error {
+	tx, err := e.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `DELETE FROM public.product_split_exclusions WHERE product_id = $1`
+
+	if _, err = tx.Exec(ctx, sql, productID); err != nil {
+		return err
+	}
+
+	sql = `INSERT INTO public.product_split_exclusions (product_id, user_id)
+			SELECT $1, unnest($2::bigint[])
+			ON CONFLICT DO NOTHING`
+
+	if _, err = tx.Exec(ctx, sql, productID, userIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (e *ExpenseRepository) CreateSettlement(ctx context.Context, settlement expense.Settlement) (uint64, error) {
+	sql := `INSERT INTO public.settlements (group_id, from_user_id, to_user_id, amount, currency, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING settlement_id`
+
+	row := e.db.QueryRow(ctx, sql,
+		settlement.GroupID,
+		settlement.FromUserID,
+		settlement.ToUserID,
+		settlement.Amount,
+		settlement.Currency,
+		settlement.CreatedAt)
+
+	var settlementID uint64
+	if err := row.Scan(&settlementID); err != nil {
+		return 0, err
+	}
+
+	return settlementID, nil
+}
+
+func (e *ExpenseRepository) GetSettlements(ctx context.Context, groupID uint64) ([]expense.SettlementDTO, error) {
+	sql := `SELECT s.settlement_id,
+				   s.from_user_id,
+				   sender.username as from_username,
+				   s.to_user_id,
+				   receiver.username as to_username,
+				   s.amount,
+				   s.currency,
+				   ROUND(s.amount * sr.rate / gr.rate, 2) as group_amount,
+				   s.created_at
+			FROM public.settlements as s
+			INNER JOIN public.groups as g
+				ON g.group_id = s.group_id
+			INNER JOIN public.exchange_rates as gr
+				ON gr.currency = g.currency
+			INNER JOIN public.exchange_rates as sr
+				ON sr.currency = s.currency
+			INNER JOIN public.users as sender
+				ON sender.user_id = s.from_user_id
+			INNER JOIN public.users as receiver
+				ON receiver.user_id = s.to_user_id
+			WHERE s.group_id = $1
+			ORDER BY s.created_at DESC, s.settlement_id DESC`
+
+	rows, err := e.db.Query(ctx, sql, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	settlements, err := pgx.CollectRows(rows, pgx.RowToStructByName[expense.SettlementDTO])
+	if err != nil {
+		return nil, err
+	}
+
+	return settlements, nil
+}
@@ -48,7 +48,10 @@ func (g *GroupRepository) GetByCode(ctx context.Context, code string) (group.Gro
 		&group.Name,
 		&group.Description,
 		&group.Code,
-		&group.CreatedAt)
+		&group.CreatedAt,
+		&group.MergeDuplicates,
+		&group.SplitRule,
+		&group.Currency)
 
 	if err != nil {
 		return group, err
@@ -68,7 +71,10 @@ func (g *GroupRepository) GetById(ctx context.Context, groupID uint64) (group.Gr
 		&group.Name,
 		&group.Description,
 		&group.Code,
-		&group.CreatedAt)
+		&group.CreatedAt,
+		&group.MergeDuplicates,
+		&group.SplitRule,
+		&group.Currency)
 
 	if err != nil {
 		return group, err
@@ -76,3 +82,11 @@ func (g *GroupRepository) GetById(ctx context.Context, groupID uint64) (group.Gr
 
 	return group, nil
 }
+
+func (g *GroupRepository) UpdateSettings(ctx context.Context, group group.Group) error {
+	sql := `UPDATE public.groups SET merge_duplicates = $1, split_rule = $2, currency = $3 WHERE group_id = $4`
+
+	_, err := g.db.Exec(ctx, sql, group.MergeDuplicates, group.SplitRule, group.Currency, group.GroupID)
+
+	return err
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/mealplan"
+	"time"
+)
+
+type MealPlanRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMealPlanRepository(db *pgxpool.Pool) *MealPlanRepository {
+	return &MealPlanRepository{db: db}
+}
+
+func (m *MealPlanRepository) Create(ctx context.Context, entry mealplan.Entry) (uint64, error) {
+	sql := `INSERT INTO public.meal_plan_entries (group_id, plan_date, slot, recipe_id, servings, text, created_by, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING entry_id`
+
+	row := m.db.QueryRow(ctx, sql,
+		entry.GroupID,
+		entry.Date,
+		entry.Slot,
+		entry.RecipeID,
+		entry.Servings,
+		entry.Text,
+		entry.CreatedBy,
+		entry.CreatedAt)
+
+	var entryID uint64
+	if err := row.Scan(&entryID); err != nil {
+		return 0, err
+	}
+
+	return entryID, nil
+}
+
+func (m *MealPlanRepository) GetById(ctx context.Context, entryID uint64) (mealplan.Entry, error) {
+	sql := `SELECT entry_id, group_id, plan_date, slot, recipe_id, servings, text, created_by, created_at
+			FROM public.meal_plan_entries
+			WHERE entry_id = $1`
+
+	row := m.db.QueryRow(ctx, sql, entryID)
+
+	var entry mealplan.Entry
+	err := row.Scan(
+		&entry.EntryID,
+		&entry.GroupID,
+		&entry.Date,
+		&entry.Slot,
+		&entry.RecipeID,
+		&entry.Servings,
+		&entry.Text,
+		&entry.CreatedBy,
+		&entry.CreatedAt)
+
+	if err != nil {
+		return mealplan.Entry{}, err
+	}
+
+	return entry, nil
+}
+
+func (m *MealPlanRepository) Update(ctx context.Context, entry mealplan.Entry) error {
+	sql := `UPDATE public.meal_plan_entries
+			SET plan_date = $1,
+			    slot = $2,
+			    recipe_id = $3,
+			    servings = $4,
+			    text = $5
+			WHERE entry_id = $6`
+
+	_, err := m.db.Exec(ctx, sql,
+		entry.Date,
+		entry.Slot,
+		entry.RecipeID,
+		entry.Servings,
+		entry.Text,
+		entry.EntryID)
+
+	return err
+}
+
+func (m *MealPlanRepository) Delete(ctx context.Context, entryID uint64) error {
+	sql := `DELETE FROM public.meal_plan_entries WHERE entry_id = $1`
+
+	_, err := m.db.Exec(ctx, sql, entryID)
+
+	return err
+}
+
+// GetEntries returns the entries planned from the first to the last day, both included, by day and slot
+func (m *MealPlanRepository) GetEntries(ctx context.Context, groupID uint64, from time.Time, to time.Time) ([]mealplan.EntryDTO, error) {
+	sql := `SELECT e.entry_id,
+				   e.plan_date,
+				   e.slot,
+				   e.recipe_id,
+				   r.name as recipe_name,
+				   e.servings,
+				   e.text,
+				   e.created_by,
+				   e.created_at
+			FROM public.meal_plan_entries as e
+			LEFT JOIN public.recipes as r
+				ON r.recipe_id = e.recipe_id
+			WHERE e.group_id = $1 AND e.plan_date BETWEEN $2 AND $3
+			ORDER BY e.plan_date,
+					 array_position(ARRAY['breakfast', 'lunch', 'dinner', 'snack'], e.slot),
+					 e.entry_id`
+
+	rows, err := m.db.Query(ctx, sql, groupID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[mealplan.EntryDTO])
+}
+
+// GetPlannedIngredients returns the ingredients of the recipes planned from the first to the last day,
+// scaled to the servings of each entry
+func (m *MealPlanRepository) GetPlannedIngredients(ctx context.Context, groupID uint64, from time.Time, to time.Time) ([]mealplan.PlannedIngredient, error) {
+	sql := `SELECT i.product_name_id,
+				   i.quantity * COALESCE(e.servings, r.servings) / r.servings as quantity,
+				   i.unit
+			FROM public.meal_plan_entries as e
+			INNER JOIN public.recipes as r
+				ON r.recipe_id = e.recipe_id
+			INNER JOIN public.recipe_ingredients as i
+				ON i.recipe_id = r.recipe_id
+			WHERE e.group_id = $1 AND e.plan_date BETWEEN $2 AND $3
+			ORDER BY e.plan_date, e.entry_id, i.ingredient_id`
+
+	rows, err := m.db.Query(ctx, sql, groupID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ingredients []mealplan.PlannedIngredient
+	for rows.Next() {
+		var ingredient mealplan.PlannedIngredient
+		if err = rows.Scan(&ingredient.ProductNameID, &ingredient.Quantity, &ingredient.Unit); err != nil {
+			return nil, err
+		}
+
+		ingredients = append(ingredients, ingredient)
+	}
+
+	return ingredients, rows.Err()
+}
@@ -49,7 +49,8 @@ func (m *MemberRepository) GetByUserId(ctx context.Context, userID uint64) (memb
 		&member.UserID,
 		&member.GroupID,
 		&member.Role,
-		&member.JoinedAt)
+		&member.JoinedAt,
+		&member.Share)
 
 	if err != nil {
 		return member, err
@@ -69,7 +70,8 @@ func (m *MemberRepository) GetByUserAndGroupId(ctx context.Context, userID uint6
 		&member.UserID,
 		&member.GroupID,
 		&member.Role,
-		&member.JoinedAt)
+		&member.JoinedAt,
+		&member.Share)
 
 	if err != nil {
 		return member, err
@@ -89,7 +91,8 @@ func (m *MemberRepository) GetByMemberAndGroupId(ctx context.Context, memberID u
 		&member.UserID,
 		&member.GroupID,
 		&member.Role,
-		&member.JoinedAt)
+		&member.JoinedAt,
+		&member.Share)
 
 	if err != nil {
 		return member, err
@@ -99,7 +102,7 @@ func (m *MemberRepository) GetByMemberAndGroupId(ctx context.Context, memberID u
 }
 
 func (m *MemberRepository) GetMembersByGroupId(ctx context.Context, groupId uint64) ([]member.MemberDTO, error) {
-	sql := `SELECT m.member_id, u.username, u.gender, m.role FROM public.members as m
+	sql := `SELECT m.member_id, u.username, u.gender, m.role, m.share FROM public.members as m
 			INNER JOIN public.users as u ON u.user_id = m.user_id
 			WHERE m.group_id = $1`
 
@@ -115,3 +118,11 @@ func (m *MemberRepository) GetMembersByGroupId(ctx context.Context, groupId uint
 
 	return members, nil
 }
+
+func (m *MemberRepository) UpdateShare(ctx context.Context, memberID uint64, share float64) error {
+	sql := `UPDATE public.members SET share = $1 WHERE member_id = $2`
+
+	_, err := m.db.Exec(ctx, sql, share, memberID)
+
+	return err
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/pantry"
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+	"time"
+)
+
+type PantryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPantryRepository(db *pgxpool.Pool) *PantryRepository {
+	return &PantryRepository{db: db}
+}
+
+// AddStock adds the quantity to the item of the product name in a compatible unit, converting it
+// to the unit of the item, or creates a new item. The earliest expiry date of the stock is kept
+func (p *PantryRepository) AddStock(ctx context.Context, groupID uint64, productNameID uint64, quantity float64, stockUnit string, expiresAt *time.Time) (uint64, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	pantryItemID, err := addStock(ctx, tx, groupID, productNameID, quantity, stockUnit, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return pantryItemID, nil
+}
+
+// addStock is AddStock inside the transaction of the caller
+func addStock(ctx context.Context, tx pgx.Tx, groupID uint64, productNameID uint64, quantity float64, stockUnit string, expiresAt *time.Time) (uint64, error) {
+	sql := `SELECT unit FROM public.pantry_items
+			WHERE group_id = $1 AND product_name_id = $2
+			ORDER BY pantry_item_id
+			FOR UPDATE`
+
+	rows, err := tx.Query(ctx, sql, groupID, productNameID)
+	if err != nil {
+		return 0, err
+	}
+
+	units, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, err
+	}
+
+	for _, itemUnit := range units {
+		if converted, ok := unit.Convert(quantity, stockUnit, itemUnit); ok {
+			quantity, stockUnit = converted, itemUnit
+			break
+		}
+	}
+
+	sql = `INSERT INTO public.pantry_items (group_id, product_name_id, quantity, unit, expires_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (group_id, product_name_id, unit) DO UPDATE
+			SET quantity = pantry_items.quantity + EXCLUDED.quantity,
+			    expires_at = CASE WHEN pantry_items.quantity = 0 THEN EXCLUDED.expires_at
+			                      ELSE LEAST(pantry_items.expires_at, EXCLUDED.expires_at) END,
+			    updated_at = EXCLUDED.updated_at
+			RETURNING pantry_item_id`
+
+	row := tx.QueryRow(ctx, sql, groupID, productNameID, quantity, stockUnit, expiresAt, time.Now().UTC())
+
+	var pantryItemID uint64
+	if err = row.Scan(&pantryItemID); err != nil {
+		return 0, err
+	}
+
+	return pantryItemID, nil
+}
+
+func (p *PantryRepository) GetById(ctx context.Context, pantryItemID uint64) (pantry.Item, error) {
+	sql := `SELECT pantry_item_id,
+				   group_id,
+				   product_name_id,
+				   quantity,
+				   unit,
+				   expires_at,
+				   low_stock_threshold,
+				   restock_quantity,
+				   updated_at
+			FROM public.pantry_items
+			WHERE pantry_item_id = $1`
+
+	row := p.db.QueryRow(ctx, sql, pantryItemID)
+
+	var item pantry.Item
+	err := row.Scan(
+		&item.PantryItemID,
+		&item.GroupID,
+		&item.ProductNameID,
+		&item.Quantity,
+		&item.Unit,
+		&item.ExpiresAt,
+		&item.LowStockThreshold,
+		&item.RestockQuantity,
+		&item.UpdatedAt)
+
+	if err != nil {
+		return pantry.Item{}, err
+	}
+
+	return item, nil
+}
+
+// GetByProductNameId returns the items of the product name in the pantry of the group, one for each unit
+func (p *PantryRepository) GetByProductNameId(ctx context.Context, groupID uint64, productNameID uint64) ([]pantry.Item, error) {
+	sql := `SELECT pantry_item_id,
+				   group_id,
+				   product_name_id,
+				   quantity,
+				   unit,
+				   expires_at,
+				   low_stock_threshold,
+				   restock_quantity,
+				   updated_at
+			FROM public.pantry_items
+			WHERE group_id = $1 AND product_name_id = $2
+			ORDER BY pantry_item_id`
+
+	rows, err := p.db.Query(ctx, sql, groupID, productNameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []pantry.Item
+	for rows.Next() {
+		var item pantry.Item
+		err = rows.Scan(
+			&item.PantryItemID,
+			&item.GroupID,
+			&item.ProductNameID,
+			&item.Quantity,
+			&item.Unit,
+			&item.ExpiresAt,
+			&item.LowStockThreshold,
+			&item.RestockQuantity,
+			&item.UpdatedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (p *PantryRepository) Update(ctx context.Context, item pantry.Item) error {
+	sql := `UPDATE public.pantry_items
+			SET quantity = $1,
+			    expires_at = $2,
+			    low_stock_threshold = $3,
+			    restock_quantity = $4,
+			    updated_at = $5
+			WHERE pantry_item_id = $6`
+
+	_, err := p.db.Exec(ctx, sql,
+		item.Quantity,
+		item.ExpiresAt,
+		item.LowStockThreshold,
+		item.RestockQuantity,
+		item.UpdatedAt,
+		item.PantryItemID)
+
+	return err
+}
+
+func (p *PantryRepository) Delete(ctx context.Context, pantryItemID uint64) error {
+	sql := `DELETE FROM public.pantry_items WHERE pantry_item_id = $1`
+
+	_, err := p.db.Exec(ctx, sql, pantryItemID)
+
+	return err
+}
+
+// Consume takes the quantity out of the stock, never below zero, and returns the stock before and after.
+// The expiry date goes away with the last of the stock
+func (p *PantryRepository) Consume(ctx context.Context, pantryItemID uint64, quantity float64) (float64, float64, error) {
+	sql := `UPDATE public.pantry_items as i
+			SET quantity = GREATEST(old.quantity - $1, 0),
+			    expires_at = CASE WHEN old.quantity - $1 > 0 THEN i.expires_at END,
+			    updated_at = $2
+			FROM (
+				SELECT quantity FROM public.pantry_items WHERE pantry_item_id = $3 FOR UPDATE
+			) as old
+			WHERE i.pantry_item_id = $3
+			RETURNING old.quantity, i.quantity`
+
+	row := p.db.QueryRow(ctx, sql, quantity, time.Now().UTC(), pantryItemID)
+
+	var before, after float64
+	if err := row.Scan(&before, &after); err != nil {
+		return 0, 0, err
+	}
+
+	return before, after, nil
+}
+
+func (p *PantryRepository) GetItems(ctx context.Context, groupID uint64, locales []string, filter pantry.Filter) ([]pantry.ItemDTO, error) {
+	sql := `SELECT i.pantry_item_id,
+				   i.product_name_id,
+				   COALESCE(pnt.name, pn.name) as product_name,
+				   pn.category_id,
+				   i.quantity,
+				   i.unit,
+				   i.expires_at,
+				   i.low_stock_threshold,
+				   i.restock_quantity,
+				   COALESCE(i.quantity <= i.low_stock_threshold, false) as low_stock,
+				   i.updated_at
+			FROM public.pantry_items as i
+			INNER JOIN public.product_names as pn
+				ON pn.product_name_id = i.product_name_id
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.product_name_translations as t
+				WHERE t.product_name_id = pn.product_name_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as pnt ON true
+			WHERE i.group_id = $1
+			  AND ($3::int = 0 OR i.expires_at <= current_date + $3::int)
+			  AND (NOT $4::boolean OR i.quantity <= i.low_stock_threshold)
+			ORDER BY i.expires_at NULLS LAST, product_name, i.pantry_item_id`
+
+	rows, err := p.db.Query(ctx, sql, groupID, locales, filter.ExpiringWithinDays, filter.LowStock)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[pantry.ItemDTO])
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/price"
+)
+
+type PriceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPriceRepository(db *pgxpool.Pool) *PriceRepository {
+	return &PriceRepository{db: db}
+}
+
+// GetPrices returns the prices the group paid for the product name in the currency of the group,
+// the newest first
+func (p *PriceRepository) GetPrices(ctx context.Context, groupID uint64, productNameID uint64) ([]price.PricePoint, error) {
+	sql := `SELECT pp.product_id,
+				   pp.quantity,
+				   pp.unit,
+				   ROUND(pp.price * fr.rate / gr.rate, 2) as price,
+				   pp.recorded_at
+			FROM public.product_prices as pp
+			INNER JOIN public.groups as g
+				ON g.group_id = pp.group_id
+			INNER JOIN public.exchange_rates as fr
+				ON fr.currency = pp.currency
+			INNER JOIN public.exchange_rates as gr
+				ON gr.currency = g.currency
+			WHERE pp.group_id = $1 AND pp.product_name_id = $2
+			ORDER BY pp.recorded_at DESC, pp.product_price_id DESC`
+
+	rows, err := p.db.Query(ctx, sql, groupID, productNameID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[price.PricePoint])
+}
+
+// recordPrice keeps the price history in line with the product: a bought product with a price
+// has its point, any other product has none. Points of removed products stay
+func recordPrice(ctx context.Context, tx pgx.Tx, productID uint64) error {
+	sql := `INSERT INTO public.product_prices (group_id, product_name_id, product_id, quantity, unit, price, currency, recorded_at)
+			SELECT p.group_id,
+				   p.product_name_id,
+				   p.product_id,
+				   p.quantity,
+				   p.unit,
+				   p.price,
+				   COALESCE(p.currency, g.currency),
+				   COALESCE(p.bought_at, p.created_at)
+			FROM public.products as p
+			INNER JOIN public.groups as g
+				ON g.group_id = p.group_id
+			WHERE p.product_id = $1 AND p.status = 'closed' AND p.price IS NOT NULL AND p.quantity > 0
+			ON CONFLICT (product_id) DO UPDATE
+			SET product_name_id = EXCLUDED.product_name_id,
+			    quantity = EXCLUDED.quantity,
+			    unit = EXCLUDED.unit,
+			    price = EXCLUDED.price,
+			    currency = EXCLUDED.currency,
+			    recorded_at = EXCLUDED.recorded_at`
+
+	tag, err := tx.Exec(ctx, sql, productID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	sql = `DELETE FROM public.product_prices WHERE product_id = $1`
+
+	_, err = tx.Exec(ctx, sql, productID)
+
+	return err
+}
@@ -1,10 +1,19 @@
 package repository
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
 	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type ProductRepository struct {
@@ -16,41 +25,443 @@ func NewProductRepository(db *pgxpool.Pool) *ProductRepository {
 }
 
 func (p *ProductRepository) Create(ctx context.Context, product product.Product) (uint64, error) {
-	sql := `INSERT INTO public.products (group_id, product_name_id, price, status, quantity, added_by, bought_by, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	productID, err := createProduct(ctx, tx, product)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return productID, nil
+}
+
+// CreateOrMerge adds the product to the oldest open product of the group with the same name
+// and a compatible unit, or creates it when there is none, it tells whether it was merged.
+// A merged product keeps its note and gets the new one appended, gets the more urgent
+// of both priorities and the earlier of both dates it's needed by
+func (p *ProductRepository) CreateOrMerge(ctx context.Context, product product.Product) (uint64, bool, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// adds of a group take turns on the lock of the group row, so two adds of a product
+	// that isn't on the list yet can't both create it
+	sql := `SELECT 1 FROM public.groups WHERE group_id = $1 FOR NO KEY UPDATE`
+
+	if _, err = tx.Exec(ctx, sql, product.GroupID); err != nil {
+		return 0, false, err
+	}
+
+	sql = `SELECT product_id, unit FROM public.products
+			WHERE group_id = $1 AND product_name_id = $2 AND status = 'open'
+			ORDER BY created_at, product_id
+			FOR UPDATE`
+
+	rows, err := tx.Query(ctx, sql, product.GroupID, product.ProductNameID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	type open struct {
+		ProductID uint64
+		Unit      string
+	}
+
+	products, err := pgx.CollectRows(rows, pgx.RowToStructByPos[open])
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, open := range products {
+		quantity, ok := unit.Convert(product.Quantity, product.Unit, open.Unit)
+		if !ok {
+			continue
+		}
+
+		sql = `UPDATE public.products
+				SET quantity = quantity + $1,
+				    note = CASE WHEN $2 = '' OR note = $2 THEN note WHEN note = '' THEN $2 ELSE note || '; ' || $2 END,
+				    priority = CASE WHEN 'urgent' IN (priority, $3) THEN 'urgent' WHEN 'normal' IN (priority, $3) THEN 'normal' ELSE priority END,
+				    needed_by = LEAST(needed_by, $4)
+				WHERE product_id = $5`
+
+		if _, err = tx.Exec(ctx, sql, quantity, product.Note, product.Priority, product.NeededBy, open.ProductID); err != nil {
+			return 0, false, err
+		}
+
+		if err = addRequest(ctx, tx, open.ProductID, product); err != nil {
+			return 0, false, err
+		}
+
+		if err = tx.Commit(ctx); err != nil {
+			return 0, false, err
+		}
+
+		return open.ProductID, true, nil
+	}
+
+	productID, err := createProduct(ctx, tx, product)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, false, err
+	}
+
+	return productID, false, nil
+}
+
+// createProduct inserts the product after the last one of its group with the request of the member who added it
+func createProduct(ctx context.Context, tx pgx.Tx, product product.Product) (uint64, error) {
+	sql := `INSERT INTO public.products (group_id, product_name_id, price, status, quantity, unit, added_by, bought_by, created_at, note, priority, needed_by, rank)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, ` + nextRank + `)
 			RETURNING product_id`
 
-	row := p.db.QueryRow(ctx, sql,
+	row := tx.QueryRow(ctx, sql,
 		product.GroupID,
 		product.ProductNameID,
 		product.Price,
 		product.Status,
 		product.Quantity,
+		product.Unit,
 		product.AddedBy,
 		product.BoughtBy,
-		product.CreatedAt)
+		product.CreatedAt,
+		product.Note,
+		product.Priority,
+		product.NeededBy)
 
 	var productID uint64
 	if err := row.Scan(&productID); err != nil {
 		return 0, err
 	}
 
+	if err := addRequest(ctx, tx, productID, product); err != nil {
+		return 0, err
+	}
+
 	return productID, nil
 }
 
+// addRequest keeps the quantity of the added product as the request of the member who added it
+func addRequest(ctx context.Context, tx pgx.Tx, productID uint64, added product.Product) error {
+	sql := `INSERT INTO public.product_requests (product_id, user_id, quantity, unit, created_at)
+			VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := tx.Exec(ctx, sql, productID, added.AddedBy, added.Quantity, added.Unit, added.CreatedAt)
+
+	return err
+}
+
+// MergeProducts adds the quantities of the open source products to the open target one, moves
+// their requests and deletes them. The rows are locked and read again, so a product bought or
+// merged meanwhile is left out, it returns how many sources were merged
+func (p *ProductRepository) MergeProducts(ctx context.Context, groupID uint64, targetID uint64, sourceIDs []uint64) (int, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// the rows are always locked in the same order, so two merges can't deadlock
+	sql := `SELECT product_id, quantity, unit FROM public.products
+			WHERE product_id = ANY($1) AND group_id = $2 AND status = 'open'
+			ORDER BY product_id
+			FOR UPDATE`
+
+	rows, err := tx.Query(ctx, sql, append([]uint64{targetID}, sourceIDs...), groupID)
+	if err != nil {
+		return 0, err
+	}
+
+	type locked struct {
+		ProductID uint64
+		Quantity  float64
+		Unit      string
+	}
+
+	products, err := pgx.CollectRows(rows, pgx.RowToStructByPos[locked])
+	if err != nil {
+		return 0, err
+	}
+
+	targetIdx := slices.IndexFunc(products, func(product locked) bool { return product.ProductID == targetID })
+	if targetIdx == -1 {
+		return 0, nil
+	}
+
+	target := products[targetIdx]
+
+	var mergedIDs []uint64
+	for _, source := range products {
+		if source.ProductID == targetID {
+			continue
+		}
+
+		quantity, ok := unit.Convert(source.Quantity, source.Unit, target.Unit)
+		if !ok {
+			continue
+		}
+
+		target.Quantity += quantity
+		mergedIDs = append(mergedIDs, source.ProductID)
+	}
+
+	if len(mergedIDs) == 0 {
+		return 0, nil
+	}
+
+	sql = `UPDATE public.products SET quantity = $1 WHERE product_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, target.Quantity, target.ProductID); err != nil {
+		return 0, err
+	}
+
+	sql = `UPDATE public.product_requests SET product_id = $1 WHERE product_id = ANY($2)`
+
+	if _, err = tx.Exec(ctx, sql, target.ProductID, mergedIDs); err != nil {
+		return 0, err
+	}
+
+	sql = `DELETE FROM public.products WHERE product_id = ANY($1) AND status = 'open'`
+
+	if _, err = tx.Exec(ctx, sql, mergedIDs); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(mergedIDs), nil
+}
+
+func (p *ProductRepository) GetOpenProductsByGroupId(ctx context.Context, groupID uint64) ([]product.Product, error) {
+	sql := `SELECT * FROM public.products WHERE group_id = $1 AND status = 'open' ORDER BY created_at, product_id`
+
+	rows, err := p.db.Query(ctx, sql, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []product.Product
+	for rows.Next() {
+		var product product.Product
+		err = rows.Scan(
+			&product.ProductID,
+			&product.GroupID,
+			&product.ProductNameID,
+			&product.Price,
+			&product.Status,
+			&product.Quantity,
+			&product.AddedBy,
+			&product.BoughtBy,
+			&product.CreatedAt,
+			&product.Unit,
+			&product.Note,
+			&product.Priority,
+			&product.NeededBy,
+			&product.AssignedTo,
+			&product.ClaimedAt,
+			&product.TripID,
+			&product.BoughtAt,
+			&product.Currency,
+			&product.Rank)
+
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+func (p *ProductRepository) GetProductRequests(ctx context.Context, productID uint64) ([]product.ProductRequestDTO, error) {
+	sql := `SELECT u.username, r.quantity, r.unit, r.created_at
+			FROM public.product_requests as r
+			INNER JOIN public.users as u
+				ON u.user_id = r.user_id
+			WHERE r.product_id = $1
+			ORDER BY r.created_at, r.product_request_id`
+
+	rows, err := p.db.Query(ctx, sql, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := pgx.CollectRows(rows, pgx.RowToStructByName[product.ProductRequestDTO])
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
 func (p *ProductRepository) Update(ctx context.Context, product product.Product) error {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = updateProduct(ctx, tx, product); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UpdateToPantry saves the bought product and adds its quantity to the pantry of the group,
+// both or none of them are done
+func (p *ProductRepository) UpdateToPantry(ctx context.Context, product product.Product, expiresAt *time.Time) error {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = updateProduct(ctx, tx, product); err != nil {
+		return err
+	}
+
+	_, err = addStock(ctx, tx, product.GroupID, product.ProductNameID, product.Quantity, product.Unit, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func updateProduct(ctx context.Context, tx pgx.Tx, product product.Product) error {
 	sql := `UPDATE public.products
 			SET price = $1,
 			    quantity = $2,
-			    status = $3,
-			    bought_by = $4
-			WHERE product_id = $5`
+			    unit = $3,
+			    status = $4,
+			    bought_by = $5,
+			    note = $6,
+			    priority = $7,
+			    needed_by = $8,
+			    bought_at = $9,
+			    currency = $10,
+			    trip_id = $11
+			WHERE product_id = $12`
+
+	_, err := tx.Exec(ctx, sql,
+		product.Price,
+		product.Quantity,
+		product.Unit,
+		product.Status,
+		product.BoughtBy,
+		product.Note,
+		product.Priority,
+		product.NeededBy,
+		product.BoughtAt,
+		product.Currency,
+		product.TripID,
+		product.ProductID)
+
+	if err != nil {
+		return err
+	}
 
-	_, err := p.db.Exec(ctx, sql, product.Price, product.Quantity, product.Status, product.BoughtBy, product.ProductID)
+	return recordPrice(ctx, tx, product.ProductID)
+}
+
+// Assign sets the member who is going to buy the product, nil releases the product
+func (p *ProductRepository) Assign(ctx context.Context, productID uint64, assignedTo *uint64, claimedAt *time.Time) error {
+	sql := `UPDATE public.products SET assigned_to = $1, claimed_at = $2 WHERE product_id = $3`
+
+	_, err := p.db.Exec(ctx, sql, assignedTo, claimedAt, productID)
 
 	return err
 }
 
+// nextRank puts a new product of the group $1 after the last one
+const nextRank = `(SELECT COALESCE(max(rank), 0) + 1 FROM public.products WHERE group_id = $1)`
+
+// Move puts the product right after or right before the anchor, it takes the middle
+// between the anchor and its neighbour, so the ranks of other products stay as they are
+func (p *ProductRepository) Move(ctx context.Context, productID uint64, anchorID uint64, after bool) error {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `SELECT group_id FROM public.products WHERE product_id = $1`
+
+	var groupID uint64
+	if err = tx.QueryRow(ctx, sql, anchorID).Scan(&groupID); err != nil {
+		return err
+	}
+
+	// moves of a group take turns on the lock of the group row, so two products moved into
+	// the same gap, one after its first row and one before its second, can't get the same rank,
+	// the lock doesn't block the products being added meanwhile
+	sql = `SELECT 1 FROM public.groups WHERE group_id = $1 FOR NO KEY UPDATE`
+
+	if _, err = tx.Exec(ctx, sql, groupID); err != nil {
+		return err
+	}
+
+	sql = `SELECT rank FROM public.products WHERE product_id = $1`
+
+	var anchor decimal.Decimal
+	if err = tx.QueryRow(ctx, sql, anchorID).Scan(&anchor); err != nil {
+		return err
+	}
+
+	sql = `SELECT rank FROM public.products
+			WHERE group_id = $1 AND product_id <> $2 AND (rank, product_id) > ($3, $4)
+			ORDER BY rank, product_id
+			LIMIT 1`
+
+	step := decimal.NewFromInt(1)
+	if !after {
+		sql = `SELECT rank FROM public.products
+				WHERE group_id = $1 AND product_id <> $2 AND (rank, product_id) < ($3, $4)
+				ORDER BY rank DESC, product_id DESC
+				LIMIT 1`
+
+		step = step.Neg()
+	}
+
+	var neighbour decimal.Decimal
+	err = tx.QueryRow(ctx, sql, groupID, productID, anchor, anchorID).Scan(&neighbour)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	// the first and the last product have no neighbour on the outer side
+	rank := anchor.Add(step)
+	if err == nil {
+		rank = anchor.Add(neighbour).Mul(decimal.NewFromFloat(0.5))
+	}
+
+	sql = `UPDATE public.products SET rank = $1 WHERE product_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, rank, productID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (p *ProductRepository) Delete(ctx context.Context, productID uint64) error {
 	sql := `DELETE FROM public.products WHERE product_id = $1`
 
@@ -74,7 +485,17 @@ func (p *ProductRepository) GetById(ctx context.Context, productID uint64) (prod
 		&product.Quantity,
 		&product.AddedBy,
 		&product.BoughtBy,
-		&product.CreatedAt)
+		&product.CreatedAt,
+		&product.Unit,
+		&product.Note,
+		&product.Priority,
+		&product.NeededBy,
+		&product.AssignedTo,
+		&product.ClaimedAt,
+		&product.TripID,
+		&product.BoughtAt,
+		&product.Currency,
+		&product.Rank)
 
 	if err != nil {
 		return product, err
@@ -83,37 +504,258 @@ func (p *ProductRepository) GetById(ctx context.Context, productID uint64) (prod
 	return product, nil
 }
 
-func (p *ProductRepository) GetGroupProducts(ctx context.Context, groupID uint64) ([]product.ProductDTO, error) {
+// timestampLayout is the layout of the timestamps in a cursor, the values funcs shadow the package
+const timestampLayout = product.CursorTimestampLayout
+
+// productSort is an order of the product listing. Every key is a non-null expression
+// with the type its cursor value is cast to, the product id always breaks the ties
+type productSort struct {
+	keys   []string
+	types  []string
+	values func(product product.ProductDTO) []string
+}
+
+var productSorts = map[string]productSort{
+	"rank": {
+		keys:  []string{"p.rank"},
+		types: product.SortTypes["rank"],
+		values: func(product product.ProductDTO) []string {
+			return []string{product.Rank.String()}
+		},
+	},
+	"created_at": {
+		keys:  []string{"p.created_at"},
+		types: product.SortTypes["created_at"],
+		values: func(product product.ProductDTO) []string {
+			return []string{product.CreatedAt.Format(timestampLayout)}
+		},
+	},
+	"category": {
+		keys:  []string{"COALESCE(ct.name, c.name)", "COALESCE(pnt.name, pn.name)"},
+		types: product.SortTypes["category"],
+		values: func(product product.ProductDTO) []string {
+			return []string{product.Category, product.ProductName}
+		},
+	},
+	"name": {
+		keys:  []string{"COALESCE(pnt.name, pn.name)"},
+		types: product.SortTypes["name"],
+		values: func(product product.ProductDTO) []string {
+			return []string{product.ProductName}
+		},
+	},
+	"price": {
+		// prices in other currencies are compared in the currency of the group, NaN goes
+		// after all numbers, so products without a price or a rate are the last
+		keys:  []string{"COALESCE(ROUND(p.price * pr.rate / gr.rate, 2), 'NaN')"},
+		types: product.SortTypes["price"],
+		values: func(product product.ProductDTO) []string {
+			if product.GroupPrice == nil {
+				return []string{"NaN"}
+			}
+
+			return []string{product.GroupPrice.String()}
+		},
+	},
+	"priority": {
+		keys:  []string{"CASE p.priority WHEN 'urgent' THEN 0 WHEN 'normal' THEN 1 ELSE 2 END", "p.created_at"},
+		types: product.SortTypes["priority"],
+		values: func(product product.ProductDTO) []string {
+			rank := map[string]string{"urgent": "0", "normal": "1"}[product.Priority]
+
+			return []string{cmp.Or(rank, "2"), product.CreatedAt.Format(timestampLayout)}
+		},
+	},
+	"store": {
+		// products of the categories the store has no section for are the last
+		keys:  []string{"COALESCE(ss.position, 2147483647)", "COALESCE(ct.name, c.name)", "COALESCE(pnt.name, pn.name)"},
+		types: product.SortTypes["store"],
+		values: func(product product.ProductDTO) []string {
+			position := "2147483647"
+			if product.SectionPosition != nil {
+				position = strconv.Itoa(*product.SectionPosition)
+			}
+
+			return []string{position, product.Category, product.ProductName}
+		},
+	},
+	"needed_by": {
+		keys:  []string{"COALESCE(p.needed_by, 'infinity')", "p.created_at"},
+		types: product.SortTypes["needed_by"],
+		values: func(product product.ProductDTO) []string {
+			neededBy := "infinity"
+			if product.NeededBy != nil {
+				neededBy = product.NeededBy.Format(time.DateOnly)
+			}
+
+			return []string{neededBy, product.CreatedAt.Format(timestampLayout)}
+		},
+	},
+}
+
+// GetGroupProducts returns up to filter.Limit products after the cursor and the cursor
+// of the next page, which is nil when there are no more products
+func (p *ProductRepository) GetGroupProducts(ctx context.Context, groupID uint64, locales []string, filter product.ProductFilter, after *product.ProductCursor) ([]product.ProductDTO, *product.ProductCursor, error) {
 	sql := `SELECT p.product_id,
-				   pn.name as product_name,
-				   c.name as category_name,
+				   p.product_name_id,
+				   COALESCE(pnt.name, pn.name) as product_name,
+				   c.category_id,
+				   COALESCE(ct.name, c.name) as category_name,
+				   p.status,
 				   p.price,
 				   p.quantity,
+				   p.unit,
+				   p.added_by as added_by_id,
 				   added.username as added_by,
+				   p.bought_by as bought_by_id,
 				   bought.username as bought_by,
-				   p.created_at
+				   p.created_at,
+				   p.note,
+				   p.priority,
+				   p.needed_by,
+				   p.assigned_to as assigned_to_id,
+				   assigned.username as assigned_to,
+				   p.claimed_at,
+				   p.bought_at,
+				   p.rank,
+				   COALESCE(p.currency, g.currency) as currency,
+				   ROUND(p.price * pr.rate / gr.rate, 2) as group_price,
+				   ss.section_id,
+				   ss.name as section_name,
+				   ss.position as section_position
 			FROM public.products as p
+			INNER JOIN public.groups as g
+				ON g.group_id = p.group_id
+			LEFT JOIN public.exchange_rates as gr
+				ON gr.currency = g.currency
+			LEFT JOIN public.exchange_rates as pr
+				ON pr.currency = COALESCE(p.currency, g.currency)
 			INNER JOIN public.users as added
 				ON added.user_id = p.added_by
 			LEFT JOIN public.users as bought
 				ON bought.user_id = p.bought_by
+			LEFT JOIN public.users as assigned
+				ON assigned.user_id = p.assigned_to
 			INNER JOIN public.product_names as pn
 				ON pn.product_name_id = p.product_name_id
 			INNER JOIN public.categories as c
 				ON c.category_id = pn.category_id
-			WHERE p.group_id = $1;`
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.product_name_translations as t
+				WHERE t.product_name_id = pn.product_name_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as pnt ON true
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.category_translations as t
+				WHERE t.category_id = c.category_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as ct ON true
+			LEFT JOIN public.store_section_categories as ssc
+				ON ssc.store_id = $3 AND ssc.category_id = c.category_id
+			LEFT JOIN public.store_sections as ss
+				ON ss.section_id = ssc.section_id
+			WHERE p.group_id = $1`
 
-	rows, err := p.db.Query(ctx, sql, groupID)
+	args := []any{groupID, locales, filter.StoreID}
+
+	// where adds the condition with its argument in place of %d
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		sql += " AND " + fmt.Sprintf(condition, len(args))
+	}
+
+	if filter.Status != "" {
+		where("p.status = $%d", filter.Status)
+	}
+
+	if filter.CategoryID != 0 {
+		where("c.category_id = $%d", filter.CategoryID)
+	}
+
+	if filter.AddedBy != 0 {
+		where("p.added_by = $%d", filter.AddedBy)
+	}
+
+	if filter.BoughtBy != 0 {
+		where("p.bought_by = $%d", filter.BoughtBy)
+	}
+
+	if filter.AssignedTo != 0 {
+		where("p.assigned_to = $%d", filter.AssignedTo)
+	}
+
+	if filter.CreatedFrom != nil {
+		where("p.created_at >= $%d", *filter.CreatedFrom)
+	}
+
+	if filter.CreatedTo != nil {
+		where("p.created_at < $%d", *filter.CreatedTo)
+	}
+
+	if filter.Query != "" {
+		where("COALESCE(pnt.name, pn.name) ILIKE '%%' || $%d || '%%'", filter.Query)
+	}
+
+	if filter.Priority != "" {
+		where("p.priority = $%d", filter.Priority)
+	}
+
+	if filter.NeededBefore != nil {
+		where("p.needed_by <= $%d", *filter.NeededBefore)
+	}
+
+	sort, ok := productSorts[filter.Sort]
+	if !ok {
+		sort = productSorts["rank"]
+	}
+
+	if after != nil {
+		if len(after.Values) != len(sort.keys) {
+			return nil, nil, fmt.Errorf("cursor has %d values, sort needs %d", len(after.Values), len(sort.keys))
+		}
+
+		values := make([]string, 0, len(sort.keys)+1)
+		for i, value := range after.Values {
+			args = append(args, value)
+			values = append(values, fmt.Sprintf("$%d::%s", len(args), sort.types[i]))
+		}
+
+		args = append(args, after.ProductID)
+		values = append(values, fmt.Sprintf("$%d", len(args)))
+
+		sql += fmt.Sprintf(" AND (%s, p.product_id) > (%s)", strings.Join(sort.keys, ", "), strings.Join(values, ", "))
+	}
+
+	// one more product tells whether there is a next page
+	args = append(args, filter.Limit+1)
+	sql += fmt.Sprintf(" ORDER BY %s, p.product_id LIMIT $%d", strings.Join(sort.keys, ", "), len(args))
+
+	rows, err := p.db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	products, err := pgx.CollectRows(rows, pgx.RowToStructByName[product.ProductDTO])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return products, nil
+	if len(products) <= filter.Limit {
+		return products, nil, nil
+	}
+
+	products = products[:filter.Limit]
+	last := products[len(products)-1]
+
+	next := &product.ProductCursor{
+		Sort:      filter.Sort,
+		Values:    sort.values(last),
+		ProductID: last.ProductID,
+	}
+
+	return products, next, nil
 }
 
 func (p *ProductRepository) GetByProductNameId(ctx context.Context, productNameID uint64) (product.ProductName, error) {
@@ -122,7 +764,12 @@ func (p *ProductRepository) GetByProductNameId(ctx context.Context, productNameI
 	row := p.db.QueryRow(ctx, sql, productNameID)
 
 	var productName product.ProductName
-	err := row.Scan(&productName.ProductNameID, &productName.CategoryID, &productName.Name)
+	err := row.Scan(
+		&productName.ProductNameID,
+		&productName.CategoryID,
+		&productName.Name,
+		&productName.GroupID,
+		&productName.DefaultUnit)
 
 	if err != nil {
 		return product.ProductName{}, err
@@ -131,10 +778,87 @@ func (p *ProductRepository) GetByProductNameId(ctx context.Context, productNameI
 	return productName, nil
 }
 
-func (p *ProductRepository) GetCategories(ctx context.Context) ([]product.Category, error) {
-	sql := `SELECT * FROM public.categories`
+func (p *ProductRepository) GetProductNameByName(ctx context.Context, groupID uint64, name string) (product.ProductName, error) {
+	sql := `SELECT * FROM public.product_names
+			WHERE lower(name) = lower($2) AND (group_id IS NULL OR group_id = $1)
+			ORDER BY group_id NULLS FIRST
+			LIMIT 1`
 
-	rows, err := p.db.Query(ctx, sql)
+	row := p.db.QueryRow(ctx, sql, groupID, name)
+
+	var productName product.ProductName
+	err := row.Scan(
+		&productName.ProductNameID,
+		&productName.CategoryID,
+		&productName.Name,
+		&productName.GroupID,
+		&productName.DefaultUnit)
+
+	if err != nil {
+		return product.ProductName{}, err
+	}
+
+	return productName, nil
+}
+
+func (p *ProductRepository) CreateProductName(ctx context.Context, productName product.ProductName) (uint64, error) {
+	sql := `INSERT INTO public.product_names (category_id, name, group_id, default_unit)
+			VALUES ($1, $2, $3, $4)
+			RETURNING product_name_id`
+
+	row := p.db.QueryRow(ctx, sql, productName.CategoryID, productName.Name, productName.GroupID, productName.DefaultUnit)
+
+	var productNameID uint64
+	if err := row.Scan(&productNameID); err != nil {
+		return 0, err
+	}
+
+	return productNameID, nil
+}
+
+func (p *ProductRepository) GetGroupProductNames(ctx context.Context, groupID uint64) ([]product.ProductName, error) {
+	sql := `SELECT * FROM public.product_names WHERE group_id = $1 ORDER BY name`
+
+	rows, err := p.db.Query(ctx, sql, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := pgx.CollectRows(rows, pgx.RowToStructByName[product.ProductName])
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (p *ProductRepository) GetCategoryById(ctx context.Context, categoryID uint64) (product.Category, error) {
+	sql := `SELECT * FROM public.categories WHERE category_id = $1`
+
+	row := p.db.QueryRow(ctx, sql, categoryID)
+
+	var category product.Category
+	err := row.Scan(&category.CategoryID, &category.Name)
+
+	if err != nil {
+		return category, err
+	}
+
+	return category, nil
+}
+
+func (p *ProductRepository) GetCategories(ctx context.Context, locales []string) ([]product.Category, error) {
+	sql := `SELECT c.category_id, COALESCE(ct.name, c.name) as name
+			FROM public.categories as c
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.category_translations as t
+				WHERE t.category_id = c.category_id AND t.locale = ANY($1::text[])
+				ORDER BY array_position($1::text[], t.locale)
+				LIMIT 1
+			) as ct ON true
+			ORDER BY c.category_id`
+
+	rows, err := p.db.Query(ctx, sql, locales)
 	if err != nil {
 		return nil, err
 	}
@@ -147,10 +871,18 @@ func (p *ProductRepository) GetCategories(ctx context.Context) ([]product.Catego
 	return categories, nil
 }
 
-func (p *ProductRepository) GetProductsByCategoryId(ctx context.Context, categoryID uint64) ([]product.ProductName, error) {
-	sql := `SELECT * FROM public.product_names WHERE category_id = $1`
+func (p *ProductRepository) GetProductsByCategoryId(ctx context.Context, categoryID uint64, groupID uint64, locales []string) ([]product.ProductName, error) {
+	sql := `SELECT pn.product_name_id, pn.category_id, COALESCE(pnt.name, pn.name) as name, pn.group_id, pn.default_unit
+			FROM public.product_names as pn
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.product_name_translations as t
+				WHERE t.product_name_id = pn.product_name_id AND t.locale = ANY($3::text[])
+				ORDER BY array_position($3::text[], t.locale)
+				LIMIT 1
+			) as pnt ON true
+			WHERE pn.category_id = $1 AND (pn.group_id IS NULL OR pn.group_id = $2)`
 
-	rows, err := p.db.Query(ctx, sql, categoryID)
+	rows, err := p.db.Query(ctx, sql, categoryID, groupID, locales)
 	if err != nil {
 		return nil, err
 	}
@@ -162,3 +894,314 @@ func (p *ProductRepository) GetProductsByCategoryId(ctx context.Context, categor
 
 	return products, nil
 }
+
+// Search matches the query variants by prefix of any word or by trigram word similarity
+// over the base and translated names visible to the user's groups, or to one of them if groupID is set
+func (p *ProductRepository) Search(ctx context.Context, userID uint64, groupID uint64, variants []string, locales []string, limit int, offset int) ([]product.SearchResultDTO, error) {
+	sql := `WITH user_groups AS (
+				SELECT group_id FROM public.members WHERE user_id = $1 AND ($6 = 0 OR group_id = $6)
+			),
+			names AS (
+				SELECT pn.product_name_id, lower(pn.name) as name
+				FROM public.product_names as pn
+				WHERE pn.group_id IS NULL OR pn.group_id IN (SELECT group_id FROM user_groups)
+				UNION ALL
+				SELECT t.product_name_id, lower(t.name) as name
+				FROM public.product_name_translations as t
+				INNER JOIN public.product_names as pn
+					ON pn.product_name_id = t.product_name_id
+				WHERE pn.group_id IS NULL OR pn.group_id IN (SELECT group_id FROM user_groups)
+			),
+			matches AS (
+				SELECT n.product_name_id,
+					   bool_or(n.name LIKE v.query || '%' OR n.name LIKE '% ' || v.query || '%') as is_prefix,
+					   max(word_similarity(v.query, n.name)) as score
+				FROM names as n
+				CROSS JOIN unnest($2::text[]) as v(query)
+				WHERE n.name LIKE v.query || '%'
+				   OR n.name LIKE '% ' || v.query || '%'
+				   OR v.query <% n.name
+				GROUP BY n.product_name_id
+			)
+			SELECT pn.product_name_id,
+				   pn.category_id,
+				   COALESCE(pnt.name, pn.name) as name,
+				   pn.group_id,
+				   (SELECT count(*) FROM public.products as p
+				    WHERE p.product_name_id = pn.product_name_id
+				      AND p.group_id IN (SELECT group_id FROM user_groups))::int as popularity,
+				   m.score::float8 as score
+			FROM matches as m
+			INNER JOIN public.product_names as pn
+				ON pn.product_name_id = m.product_name_id
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.product_name_translations as t
+				WHERE t.product_name_id = pn.product_name_id AND t.locale = ANY($3::text[])
+				ORDER BY array_position($3::text[], t.locale)
+				LIMIT 1
+			) as pnt ON true
+			ORDER BY m.is_prefix DESC, popularity DESC, m.score DESC, name, pn.product_name_id
+			LIMIT $4 OFFSET $5`
+
+	rows, err := p.db.Query(ctx, sql, userID, variants, locales, limit, offset, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := pgx.CollectRows(rows, pgx.RowToStructByName[product.SearchResultDTO])
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (p *ProductRepository) ImportTranslations(ctx context.Context, translations product.Translations) (int, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var imported int
+
+	for _, category := range translations.Categories {
+		sql := `INSERT INTO public.category_translations (category_id, locale, name)
+				SELECT category_id, $2, $3 FROM public.categories WHERE category_id = $1
+				ON CONFLICT (category_id, locale) DO UPDATE SET name = excluded.name`
+
+		tag, err := tx.Exec(ctx, sql, category.ID, translations.Locale, category.Name)
+		if err != nil {
+			return 0, err
+		}
+
+		imported += int(tag.RowsAffected())
+	}
+
+	for _, productName := range translations.ProductNames {
+		sql := `INSERT INTO public.product_name_translations (product_name_id, locale, name)
+				SELECT product_name_id, $2, $3 FROM public.product_names WHERE product_name_id = $1
+				ON CONFLICT (product_name_id, locale) DO UPDATE SET name = excluded.name`
+
+		tag, err := tx.Exec(ctx, sql, productName.ID, translations.Locale, productName.Name)
+		if err != nil {
+			return 0, err
+		}
+
+		imported += int(tag.RowsAffected())
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return imported, nil
+}
+
+func (p *ProductRepository) CreateCategory(ctx context.Context, category product.Category) (uint64, error) {
+	sql := `INSERT INTO public.categories (name) VALUES ($1) RETURNING category_id`
+
+	row := p.db.QueryRow(ctx, sql, category.Name)
+
+	var categoryID uint64
+	if err := row.Scan(&categoryID); err != nil {
+		return 0, err
+	}
+
+	return categoryID, nil
+}
+
+func (p *ProductRepository) UpdateCategory(ctx context.Context, category product.Category) error {
+	sql := `UPDATE public.categories SET name = $1 WHERE category_id = $2`
+
+	_, err := p.db.Exec(ctx, sql, category.Name, category.CategoryID)
+
+	return err
+}
+
+func (p *ProductRepository) DeleteCategory(ctx context.Context, categoryID uint64) error {
+	sql := `DELETE FROM public.categories WHERE category_id = $1`
+
+	_, err := p.db.Exec(ctx, sql, categoryID)
+
+	return err
+}
+
+func (p *ProductRepository) CountProductNamesByCategoryId(ctx context.Context, categoryID uint64) (int, error) {
+	sql := `SELECT count(*) FROM public.product_names WHERE category_id = $1`
+
+	row := p.db.QueryRow(ctx, sql, categoryID)
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *ProductRepository) MergeCategories(ctx context.Context, sourceID uint64, targetID uint64) error {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `UPDATE public.product_names SET category_id = $1 WHERE category_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	// budgets of groups that already have one for the target category go away with the source category
+	sql = `UPDATE public.budgets as b SET category_id = $1
+			WHERE b.category_id = $2 AND NOT EXISTS (
+				SELECT 1 FROM public.budgets as t WHERE t.group_id = b.group_id AND t.category_id = $1
+			)`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	// a store that has both categories keeps the section of the target
+	sql = `UPDATE public.store_section_categories as sc SET category_id = $1
+			WHERE sc.category_id = $2 AND NOT EXISTS (
+				SELECT 1 FROM public.store_section_categories as t WHERE t.store_id = sc.store_id AND t.category_id = $1
+			)`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	sql = `DELETE FROM public.categories WHERE category_id = $1`
+
+	if _, err = tx.Exec(ctx, sql, sourceID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *ProductRepository) UpdateProductName(ctx context.Context, productName product.ProductName) error {
+	sql := `UPDATE public.product_names SET category_id = $1, name = $2, default_unit = $3 WHERE product_name_id = $4`
+
+	_, err := p.db.Exec(ctx, sql, productName.CategoryID, productName.Name, productName.DefaultUnit, productName.ProductNameID)
+
+	return err
+}
+
+func (p *ProductRepository) DeleteProductName(ctx context.Context, productNameID uint64) error {
+	sql := `DELETE FROM public.product_names WHERE product_name_id = $1`
+
+	_, err := p.db.Exec(ctx, sql, productNameID)
+
+	return err
+}
+
+// productNameTables are the tables whose rows are deleted together with their product name
+var productNameTables = []string{"products", "product_prices", "recurring_products", "list_template_items", "pantry_items", "recipe_ingredients", "barcodes"}
+
+// CountProductNameUses counts the rows of all tables that refer to the product name
+func (p *ProductRepository) CountProductNameUses(ctx context.Context, productNameID uint64) (int, error) {
+	counts := make([]string, 0, len(productNameTables))
+	for _, table := range productNameTables {
+		counts = append(counts, fmt.Sprintf("(SELECT count(*) FROM public.%s WHERE product_name_id = $1)", table))
+	}
+
+	sql := "SELECT " + strings.Join(counts, " + ")
+
+	row := p.db.QueryRow(ctx, sql, productNameID)
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *ProductRepository) MergeProductNames(ctx context.Context, sourceID uint64, targetID uint64) error {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `UPDATE public.products SET product_name_id = $1 WHERE product_name_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	sql = `UPDATE public.product_prices SET product_name_id = $1 WHERE product_name_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	sql = `UPDATE public.recurring_products SET product_name_id = $1 WHERE product_name_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	sql = `UPDATE public.list_template_items SET product_name_id = $1 WHERE product_name_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	sql = `UPDATE public.recipe_ingredients SET product_name_id = $1 WHERE product_name_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	sql = `UPDATE public.barcodes SET product_name_id = $1 WHERE product_name_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	// a group that dismissed both names keeps its dismissal of the target
+	sql = `UPDATE public.suggestion_dismissals as d SET product_name_id = $1
+			WHERE d.product_name_id = $2 AND NOT EXISTS (
+				SELECT 1 FROM public.suggestion_dismissals as t
+				WHERE t.group_id = d.group_id AND t.product_name_id = $1
+			)`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	// stock in the same unit is added to the stock of the target
+	sql = `UPDATE public.pantry_items as t
+			SET quantity = t.quantity + s.quantity,
+			    expires_at = LEAST(t.expires_at, s.expires_at),
+			    updated_at = GREATEST(t.updated_at, s.updated_at)
+			FROM public.pantry_items as s
+			WHERE s.product_name_id = $2 AND t.product_name_id = $1
+			  AND t.group_id = s.group_id AND t.unit = s.unit`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	sql = `UPDATE public.pantry_items as s SET product_name_id = $1
+			WHERE s.product_name_id = $2 AND NOT EXISTS (
+				SELECT 1 FROM public.pantry_items as t
+				WHERE t.group_id = s.group_id AND t.unit = s.unit AND t.product_name_id = $1
+			)`
+
+	if _, err = tx.Exec(ctx, sql, targetID, sourceID); err != nil {
+		return err
+	}
+
+	sql = `DELETE FROM public.product_names WHERE product_name_id = $1`
+
+	if _, err = tx.Exec(ctx, sql, sourceID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
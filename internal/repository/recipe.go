@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/recipe"
+)
+
+type RecipeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRecipeRepository(db *pgxpool.Pool) *RecipeRepository {
+	return &RecipeRepository{db: db}
+}
+
+func (r *RecipeRepository) Create(ctx context.Context, rcp recipe.Recipe, ingredients []recipe.Ingredient) (uint64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `INSERT INTO public.recipes (group_id, name, servings, created_by, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING recipe_id`
+
+	row := tx.QueryRow(ctx, sql, rcp.GroupID, rcp.Name, rcp.Servings, rcp.CreatedBy, rcp.CreatedAt)
+
+	var recipeID uint64
+	if err = row.Scan(&recipeID); err != nil {
+		return 0, err
+	}
+
+	if err = insertIngredients(ctx, tx, recipeID, ingredients); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return recipeID, nil
+}
+
+func (r *RecipeRepository) GetById(ctx context.Context, recipeID uint64) (recipe.Recipe, error) {
+	sql := `SELECT recipe_id, group_id, name, servings, created_by, created_at FROM public.recipes WHERE recipe_id = $1`
+
+	row := r.db.QueryRow(ctx, sql, recipeID)
+
+	var rcp recipe.Recipe
+	err := row.Scan(
+		&rcp.RecipeID,
+		&rcp.GroupID,
+		&rcp.Name,
+		&rcp.Servings,
+		&rcp.CreatedBy,
+		&rcp.CreatedAt)
+
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+
+	return rcp, nil
+}
+
+func (r *RecipeRepository) GetIngredients(ctx context.Context, recipeID uint64) ([]recipe.Ingredient, error) {
+	sql := `SELECT ingredient_id, recipe_id, product_name_id, quantity, unit, note
+			FROM public.recipe_ingredients
+			WHERE recipe_id = $1
+			ORDER BY ingredient_id`
+
+	rows, err := r.db.Query(ctx, sql, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ingredients []recipe.Ingredient
+	for rows.Next() {
+		var ingredient recipe.Ingredient
+		err = rows.Scan(
+			&ingredient.IngredientID,
+			&ingredient.RecipeID,
+			&ingredient.ProductNameID,
+			&ingredient.Quantity,
+			&ingredient.Unit,
+			&ingredient.Note)
+
+		if err != nil {
+			return nil, err
+		}
+
+		ingredients = append(ingredients, ingredient)
+	}
+
+	return ingredients, rows.Err()
+}
+
+// Update saves the name and the servings of the recipe and replaces its ingredients unless ingredients is nil
+func (r *RecipeRepository) Update(ctx context.Context, rcp recipe.Recipe, ingredients []recipe.Ingredient) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `UPDATE public.recipes SET name = $1, servings = $2 WHERE recipe_id = $3`
+
+	if _, err = tx.Exec(ctx, sql, rcp.Name, rcp.Servings, rcp.RecipeID); err != nil {
+		return err
+	}
+
+	if ingredients != nil {
+		sql = `DELETE FROM public.recipe_ingredients WHERE recipe_id = $1`
+
+		if _, err = tx.Exec(ctx, sql, rcp.RecipeID); err != nil {
+			return err
+		}
+
+		if err = insertIngredients(ctx, tx, rcp.RecipeID, ingredients); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *RecipeRepository) Delete(ctx context.Context, recipeID uint64) error {
+	sql := `DELETE FROM public.recipes WHERE recipe_id = $1`
+
+	_, err := r.db.Exec(ctx, sql, recipeID)
+
+	return err
+}
+
+// GetRecipes returns the recipes of the group by name
+func (r *RecipeRepository) GetRecipes(ctx context.Context, groupID uint64) ([]recipe.RecipeDTO, error) {
+	sql := `SELECT recipe_id, name, servings, created_by, created_at
+			FROM public.recipes
+			WHERE group_id = $1
+			ORDER BY name, recipe_id`
+
+	rows, err := r.db.Query(ctx, sql, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[recipe.RecipeDTO])
+}
+
+func (r *RecipeRepository) GetRecipeIngredients(ctx context.Context, recipeIDs []uint64, locales []string) ([]recipe.IngredientDTO, error) {
+	sql := `SELECT i.ingredient_id,
+				   i.recipe_id,
+				   i.product_name_id,
+				   COALESCE(pnt.name, pn.name) as product_name,
+				   i.quantity,
+				   i.unit,
+				   i.note
+			FROM public.recipe_ingredients as i
+			INNER JOIN public.product_names as pn
+				ON pn.product_name_id = i.product_name_id
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.product_name_translations as t
+				WHERE t.product_name_id = pn.product_name_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as pnt ON true
+			WHERE i.recipe_id = ANY($1::bigint[])
+			ORDER BY i.recipe_id, i.ingredient_id`
+
+	rows, err := r.db.Query(ctx, sql, recipeIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[recipe.IngredientDTO])
+}
+
+func insertIngredients(ctx context.Context, tx pgx.Tx, recipeID uint64, ingredients []recipe.Ingredient) error {
+	sql := `INSERT INTO public.recipe_ingredients (recipe_id, product_name_id, quantity, unit, note)
+			VALUES ($1, $2, $3, $4, $5)`
+
+	for _, ingredient := range ingredients {
+		if _, err := tx.Exec(ctx, sql, recipeID, ingredient.ProductNameID, ingredient.Quantity, ingredient.Unit, ingredient.Note); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/product"
+	"github.com/tclutin/shoppinglist-api/internal/domain/recurring"
+	"time"
+)
+
+type RecurringRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRecurringRepository(db *pgxpool.Pool) *RecurringRepository {
+	return &RecurringRepository{db: db}
+}
+
+func (r *RecurringRepository) Create(ctx context.Context, rule recurring.Rule) (uint64, error) {
+	sql := `INSERT INTO public.recurring_products (group_id, product_name_id, quantity, unit, note, cron, every_days, next_run_at, created_by, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING recurring_product_id`
+
+	row := r.db.QueryRow(ctx, sql,
+		rule.GroupID,
+		rule.ProductNameID,
+		rule.Quantity,
+		rule.Unit,
+		rule.Note,
+		rule.Cron,
+		rule.EveryDays,
+		rule.NextRunAt,
+		rule.CreatedBy,
+		rule.CreatedAt)
+
+	var recurringProductID uint64
+	if err := row.Scan(&recurringProductID); err != nil {
+		return 0, err
+	}
+
+	return recurringProductID, nil
+}
+
+func (r *RecurringRepository) GetById(ctx context.Context, recurringProductID uint64) (recurring.Rule, error) {
+	sql := `SELECT recurring_product_id,
+				   group_id,
+				   product_name_id,
+				   quantity,
+				   unit,
+				   note,
+				   cron,
+				   every_days,
+				   product_id,
+				   next_run_at,
+				   created_by,
+				   created_at
+			FROM public.recurring_products
+			WHERE recurring_product_id = $1`
+
+	row := r.db.QueryRow(ctx, sql, recurringProductID)
+
+	return scanRule(row)
+}
+
+func (r *RecurringRepository) Update(ctx context.Context, rule recurring.Rule) error {
+	sql := `UPDATE public.recurring_products
+			SET quantity = $1,
+			    unit = $2,
+			    note = $3,
+			    cron = $4,
+			    every_days = $5,
+			    next_run_at = $6
+			WHERE recurring_product_id = $7`
+
+	_, err := r.db.Exec(ctx, sql,
+		rule.Quantity,
+		rule.Unit,
+		rule.Note,
+		rule.Cron,
+		rule.EveryDays,
+		rule.NextRunAt,
+		rule.RecurringProductID)
+
+	return err
+}
+
+func (r *RecurringRepository) Delete(ctx context.Context, recurringProductID uint64) error {
+	sql := `DELETE FROM public.recurring_products WHERE recurring_product_id = $1`
+
+	_, err := r.db.Exec(ctx, sql, recurringProductID)
+
+	return err
+}
+
+func (r *RecurringRepository) GetRules(ctx context.Context, groupID uint64, locales []string) ([]recurring.RuleDTO, error) {
+	sql := `SELECT rp.recurring_product_id,
+				   rp.product_name_id,
+				   COALESCE(pnt.name, pn.name) as product_name,
+				   rp.quantity,
+				   rp.unit,
+				   rp.note,
+				   rp.cron,
+				   rp.every_days,
+				   rp.product_id,
+				   p.status as product_status,
+				   rp.next_run_at,
+				   rp.created_by,
+				   rp.created_at
+			FROM public.recurring_products as rp
+			INNER JOIN public.product_names as pn
+				ON pn.product_name_id = rp.product_name_id
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.product_name_translations as t
+				WHERE t.product_name_id = pn.product_name_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as pnt ON true
+			LEFT JOIN public.products as p
+				ON p.product_id = rp.product_id
+			WHERE rp.group_id = $1
+			ORDER BY rp.created_at, rp.recurring_product_id`
+
+	rows, err := r.db.Query(ctx, sql, groupID, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[recurring.RuleDTO])
+}
+
+// CreateDue puts the products of the rules due at now on the lists in one transaction and moves the rules
+// to their next run. The advisory lock lets one replica of the app run it at a time and the rows stay locked
+// until the commit, so a product is never added twice. A rule whose product is still open is only moved on,
+// an open product of the same name and unit added by hand is taken over instead of adding a duplicate
+func (r *RecurringRepository) CreateDue(ctx context.Context, now time.Time, next func(rule recurring.Rule, now time.Time) time.Time) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err = tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('recurring_products'))`).Scan(&locked); err != nil {
+		return 0, err
+	}
+
+	if !locked {
+		return 0, nil
+	}
+
+	// a rule repeated after buying waits for its product to be bought, next_run_at only
+	// matters for it while the product is open or removed from the list
+	sql := `SELECT rp.recurring_product_id,
+				   rp.group_id,
+				   rp.product_name_id,
+				   rp.quantity,
+				   rp.unit,
+				   rp.note,
+				   rp.cron,
+				   rp.every_days,
+				   rp.product_id,
+				   rp.next_run_at,
+				   rp.created_by,
+				   rp.created_at,
+				   COALESCE(p.status = 'open', false) as is_open
+			FROM public.recurring_products as rp
+			LEFT JOIN public.products as p
+				ON p.product_id = rp.product_id
+			WHERE (rp.next_run_at <= $1 AND (rp.every_days IS NULL OR p.status IS DISTINCT FROM 'closed'))
+			   OR (rp.every_days IS NOT NULL AND p.status = 'closed' AND p.bought_at + rp.every_days * interval '1 day' <= $1)
+			ORDER BY rp.recurring_product_id
+			FOR UPDATE OF rp SKIP LOCKED`
+
+	rows, err := tx.Query(ctx, sql, now)
+	if err != nil {
+		return 0, err
+	}
+
+	type dueRule struct {
+		rule   recurring.Rule
+		isOpen bool
+	}
+
+	var due []dueRule
+	for rows.Next() {
+		var d dueRule
+		err = rows.Scan(
+			&d.rule.RecurringProductID,
+			&d.rule.GroupID,
+			&d.rule.ProductNameID,
+			&d.rule.Quantity,
+			&d.rule.Unit,
+			&d.rule.Note,
+			&d.rule.Cron,
+			&d.rule.EveryDays,
+			&d.rule.ProductID,
+			&d.rule.NextRunAt,
+			&d.rule.CreatedBy,
+			&d.rule.CreatedAt,
+			&d.isOpen)
+
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		due = append(due, d)
+	}
+
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	sql = `UPDATE public.recurring_products SET product_id = $1, next_run_at = $2 WHERE recurring_product_id = $3`
+
+	var created int
+	for _, d := range due {
+		productID := d.rule.ProductID
+
+		if !d.isOpen {
+			id, err := putOnList(ctx, tx, d.rule, now)
+			if err != nil {
+				return 0, err
+			}
+
+			productID = &id
+			created++
+		}
+
+		if _, err = tx.Exec(ctx, sql, productID, next(d.rule, now), d.rule.RecurringProductID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+// putOnList returns the open product of the group with the name and unit of the rule, adding it when there is none
+func putOnList(ctx context.Context, tx pgx.Tx, rule recurring.Rule, now time.Time) (uint64, error) {
+	sql := `SELECT product_id
+			FROM public.products
+			WHERE group_id = $1 AND product_name_id = $2 AND unit = $3 AND status = 'open'
+			ORDER BY product_id
+			LIMIT 1`
+
+	var productID uint64
+
+	err := tx.QueryRow(ctx, sql, rule.GroupID, rule.ProductNameID, rule.Unit).Scan(&productID)
+	if err == nil {
+		return productID, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+
+	sql = `INSERT INTO public.products (group_id, product_name_id, status, quantity, unit, added_by, created_at, note, priority, rank)
+			VALUES ($1, $2, 'open', $3, $4, $5, $6, $7, $8, ` + nextRank + `)
+			RETURNING product_id`
+
+	row := tx.QueryRow(ctx, sql,
+		rule.GroupID,
+		rule.ProductNameID,
+		rule.Quantity,
+		rule.Unit,
+		rule.CreatedBy,
+		now,
+		rule.Note,
+		product.PriorityNormal)
+
+	if err = row.Scan(&productID); err != nil {
+		return 0, err
+	}
+
+	sql = `INSERT INTO public.product_requests (product_id, user_id, quantity, unit, created_at)
+			VALUES ($1, $2, $3, $4, $5)`
+
+	if _, err = tx.Exec(ctx, sql, productID, rule.CreatedBy, rule.Quantity, rule.Unit, now); err != nil {
+		return 0, err
+	}
+
+	return productID, nil
+}
+
+func scanRule(row pgx.Row) (recurring.Rule, error) {
+	var rule recurring.Rule
+	err := row.Scan(
+		&rule.RecurringProductID,
+		&rule.GroupID,
+		&rule.ProductNameID,
+		&rule.Quantity,
+		&rule.Unit,
+		&rule.Note,
+		&rule.Cron,
+		&rule.EveryDays,
+		&rule.ProductID,
+		&rule.NextRunAt,
+		&rule.CreatedBy,
+		&rule.CreatedAt)
+
+	if err != nil {
+		return recurring.Rule{}, err
+	}
+
+	return rule, nil
+}
@@ -3,19 +3,47 @@ package repository
 import "github.com/jackc/pgx/v5/pgxpool"
 
 type Repository struct {
-	User    *UserRepository
-	Session *SessionRepository
-	Group   *GroupRepository
-	Member  *MemberRepository
-	Product *ProductRepository
+	User       *UserRepository
+	Session    *SessionRepository
+	Group      *GroupRepository
+	Member     *MemberRepository
+	Product    *ProductRepository
+	Trip       *TripRepository
+	Expense    *ExpenseRepository
+	Stats      *StatsRepository
+	Price      *PriceRepository
+	Budget     *BudgetRepository
+	Currency   *CurrencyRepository
+	Recurring  *RecurringRepository
+	Template   *TemplateRepository
+	Suggestion *SuggestionRepository
+	Pantry     *PantryRepository
+	Recipe     *RecipeRepository
+	MealPlan   *MealPlanRepository
+	Store      *StoreRepository
+	Barcode    *BarcodeRepository
 }
 
 func NewRepositories(pool *pgxpool.Pool) *Repository {
 	return &Repository{
-		User:    NewUserRepository(pool),
-		Session: NewSessionRepository(pool),
-		Group:   NewGroupRepository(pool),
-		Member:  NewMemberRepository(pool),
-		Product: NewProductRepository(pool),
+		User:       NewUserRepository(pool),
+		Session:    NewSessionRepository(pool),
+		Group:      NewGroupRepository(pool),
+		Member:     NewMemberRepository(pool),
+		Product:    NewProductRepository(pool),
+		Trip:       NewTripRepository(pool),
+		Expense:    NewExpenseRepository(pool),
+		Stats:      NewStatsRepository(pool),
+		Price:      NewPriceRepository(pool),
+		Budget:     NewBudgetRepository(pool),
+		Currency:   NewCurrencyRepository(pool),
+		Recurring:  NewRecurringRepository(pool),
+		Template:   NewTemplateRepository(pool),
+		Suggestion: NewSuggestionRepository(pool),
+		Pantry:     NewPantryRepository(pool),
+		Recipe:     NewRecipeRepository(pool),
+		MealPlan:   NewMealPlanRepository(pool),
+		Store:      NewStoreRepository(pool),
+		Barcode:    NewBarcodeRepository(pool),
 	}
 }
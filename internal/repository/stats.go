@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/stats"
+)
+
+// boughtItems is the CTE of the products bought by the group in the period with localized names
+// and prices converted to the currency of the group, its parameters are $1 group_id, $2 locales,
+// $3 from and $4 to
+const boughtItems = `WITH items AS (
+			SELECT p.product_id,
+				   p.product_name_id,
+				   COALESCE(pnt.name, pn.name) as product_name,
+				   c.category_id,
+				   COALESCE(ct.name, c.name) as category_name,
+				   p.quantity,
+				   p.unit,
+				   ROUND(p.price * pr.rate / gr.rate, 2) as price,
+				   p.price as original_price,
+				   COALESCE(p.currency, g.currency) as currency,
+				   p.bought_by,
+				   p.trip_id,
+				   COALESCE(p.bought_at, p.created_at) as bought_at
+			FROM public.products as p
+			INNER JOIN public.groups as g
+				ON g.group_id = p.group_id
+			INNER JOIN public.exchange_rates as gr
+				ON gr.currency = g.currency
+			INNER JOIN public.exchange_rates as pr
+				ON pr.currency = COALESCE(p.currency, g.currency)
+			INNER JOIN public.product_names as pn
+				ON pn.product_name_id = p.product_name_id
+			INNER JOIN public.categories as c
+				ON c.category_id = pn.category_id
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.product_name_translations as t
+				WHERE t.product_name_id = pn.product_name_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as pnt ON true
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.category_translations as t
+				WHERE t.category_id = c.category_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as ct ON true
+			WHERE p.group_id = $1
+			  AND p.status = 'closed'
+			  AND ($3::timestamp IS NULL OR COALESCE(p.bought_at, p.created_at) >= $3)
+			  AND ($4::timestamp IS NULL OR COALESCE(p.bought_at, p.created_at) < $4)
+		)
+		`
+
+type StatsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewStatsRepository(db *pgxpool.Pool) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+func (s *StatsRepository) GetTotals(ctx context.Context, dto stats.PeriodDTO) (stats.TotalsDTO, error) {
+	sql := boughtItems + `SELECT COUNT(*) as items, COUNT(price) as priced_items, COALESCE(SUM(price), 0) as total
+		FROM items`
+
+	rows, err := s.db.Query(ctx, sql, dto.GroupID, dto.Locales, dto.From, dto.To)
+	if err != nil {
+		return stats.TotalsDTO{}, err
+	}
+
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[stats.TotalsDTO])
+}
+
+func (s *StatsRepository) GetTotalsByCategory(ctx context.Context, dto stats.PeriodDTO) ([]stats.CategoryTotalDTO, error) {
+	sql := boughtItems + `SELECT category_id, category_name, COUNT(*) as items, COALESCE(SUM(price), 0) as total
+		FROM items
+		GROUP BY category_id, category_name
+		ORDER BY total DESC, category_id`
+
+	rows, err := s.db.Query(ctx, sql, dto.GroupID, dto.Locales, dto.From, dto.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[stats.CategoryTotalDTO])
+}
+
+func (s *StatsRepository) GetTotalsByMember(ctx context.Context, dto stats.PeriodDTO) ([]stats.MemberTotalDTO, error) {
+	sql := boughtItems + `SELECT i.bought_by as user_id, u.username, COUNT(*) as items, COALESCE(SUM(i.price), 0) as total
+		FROM items as i
+		LEFT JOIN public.users as u
+			ON u.user_id = i.bought_by
+		GROUP BY i.bought_by, u.username
+		ORDER BY total DESC, i.bought_by`
+
+	rows, err := s.db.Query(ctx, sql, dto.GroupID, dto.Locales, dto.From, dto.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[stats.MemberTotalDTO])
+}
+
+func (s *StatsRepository) GetTotalsByMonth(ctx context.Context, dto stats.PeriodDTO) ([]stats.MonthTotalDTO, error) {
+	sql := boughtItems + `SELECT to_char(date_trunc('month', bought_at), 'YYYY-MM') as month,
+			   COUNT(*) as items,
+			   COALESCE(SUM(price), 0) as total
+		FROM items
+		GROUP BY month
+		ORDER BY month`
+
+	rows, err := s.db.Query(ctx, sql, dto.GroupID, dto.Locales, dto.From, dto.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[stats.MonthTotalDTO])
+}
+
+func (s *StatsRepository) GetAveragePrices(ctx context.Context, dto stats.PeriodDTO) ([]stats.AveragePriceDTO, error) {
+	sql := boughtItems + `SELECT product_name_id,
+			   product_name,
+			   unit,
+			   COUNT(*) as items,
+			   ROUND(AVG(price), 2) as price,
+			   ROUND(SUM(price) / NULLIF(SUM(quantity), 0), 2) as unit_price
+		FROM items
+		WHERE price IS NOT NULL
+		GROUP BY product_name_id, product_name, unit
+		HAVING SUM(quantity) > 0
+		ORDER BY product_name, unit`
+
+	rows, err := s.db.Query(ctx, sql, dto.GroupID, dto.Locales, dto.From, dto.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[stats.AveragePriceDTO])
+}
+
+func (s *StatsRepository) GetTopItems(ctx context.Context, dto stats.PeriodDTO) ([]stats.TopItemDTO, error) {
+	sql := boughtItems + `SELECT product_name_id, product_name, COUNT(*) as items, COALESCE(SUM(price), 0) as total
+		FROM items
+		GROUP BY product_name_id, product_name
+		ORDER BY items DESC, total DESC, product_name_id
+		LIMIT $5`
+
+	rows, err := s.db.Query(ctx, sql, dto.GroupID, dto.Locales, dto.From, dto.To, dto.Top)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[stats.TopItemDTO])
+}
+
+func (s *StatsRepository) GetItems(ctx context.Context, dto stats.PeriodDTO) ([]stats.ItemDTO, error) {
+	sql := boughtItems + `SELECT i.product_id,
+			   i.bought_at,
+			   i.product_name,
+			   i.category_name,
+			   i.quantity,
+			   i.unit,
+			   i.original_price,
+			   i.currency,
+			   i.price,
+			   u.username as bought_by,
+			   i.trip_id,
+			   t.store
+		FROM items as i
+		LEFT JOIN public.users as u
+			ON u.user_id = i.bought_by
+		LEFT JOIN public.trips as t
+			ON t.trip_id = i.trip_id
+		ORDER BY i.bought_at, i.product_id`
+
+	rows, err := s.db.Query(ctx, sql, dto.GroupID, dto.Locales, dto.From, dto.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[stats.ItemDTO])
+}
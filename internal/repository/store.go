@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/store"
+)
+
+type StoreRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewStoreRepository(db *pgxpool.Pool) *StoreRepository {
+	return &StoreRepository{db: db}
+}
+
+func (s *StoreRepository) Create(ctx context.Context, str store.Store, sections []store.Section) (uint64, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `INSERT INTO public.stores (group_id, name, created_by, created_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING store_id`
+
+	row := tx.QueryRow(ctx, sql, str.GroupID, str.Name, str.CreatedBy, str.CreatedAt)
+
+	var storeID uint64
+	if err = row.Scan(&storeID); err != nil {
+		return 0, err
+	}
+
+	if err = insertSections(ctx, tx, storeID, sections); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return storeID, nil
+}
+
+func (s *StoreRepository) GetById(ctx context.Context, storeID uint64) (store.Store, error) {
+	sql := `SELECT store_id, group_id, name, created_by, created_at FROM public.stores WHERE store_id = $1`
+
+	row := s.db.QueryRow(ctx, sql, storeID)
+
+	var str store.Store
+	err := row.Scan(
+		&str.StoreID,
+		&str.GroupID,
+		&str.Name,
+		&str.CreatedBy,
+		&str.CreatedAt)
+
+	if err != nil {
+		return store.Store{}, err
+	}
+
+	return str, nil
+}
+
+// GetStoreGroupId returns the group the store belongs to
+func (s *StoreRepository) GetStoreGroupId(ctx context.Context, storeID uint64) (uint64, error) {
+	sql := `SELECT group_id FROM public.stores WHERE store_id = $1`
+
+	var groupID uint64
+	if err := s.db.QueryRow(ctx, sql, storeID).Scan(&groupID); err != nil {
+		return 0, err
+	}
+
+	return groupID, nil
+}
+
+// Update renames the store and replaces its sections unless sections is nil
+func (s *StoreRepository) Update(ctx context.Context, str store.Store, sections []store.Section) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `UPDATE public.stores SET name = $1 WHERE store_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, str.Name, str.StoreID); err != nil {
+		return err
+	}
+
+	if sections != nil {
+		sql = `DELETE FROM public.store_sections WHERE store_id = $1`
+
+		if _, err = tx.Exec(ctx, sql, str.StoreID); err != nil {
+			return err
+		}
+
+		if err = insertSections(ctx, tx, str.StoreID, sections); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *StoreRepository) Delete(ctx context.Context, storeID uint64) error {
+	sql := `DELETE FROM public.stores WHERE store_id = $1`
+
+	_, err := s.db.Exec(ctx, sql, storeID)
+
+	return err
+}
+
+// GetStores returns the stores of the group by name
+func (s *StoreRepository) GetStores(ctx context.Context, groupID uint64) ([]store.StoreDTO, error) {
+	sql := `SELECT store_id, name, created_by, created_at
+			FROM public.stores
+			WHERE group_id = $1
+			ORDER BY name, store_id`
+
+	rows, err := s.db.Query(ctx, sql, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[store.StoreDTO])
+}
+
+func (s *StoreRepository) GetSections(ctx context.Context, storeIDs []uint64) ([]store.SectionDTO, error) {
+	sql := `SELECT section_id, store_id, name, position
+			FROM public.store_sections
+			WHERE store_id = ANY($1::bigint[])
+			ORDER BY store_id, position`
+
+	rows, err := s.db.Query(ctx, sql, storeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[store.SectionDTO])
+}
+
+func (s *StoreRepository) GetSectionCategories(ctx context.Context, sectionIDs []uint64, locales []string) ([]store.SectionCategoryDTO, error) {
+	sql := `SELECT sc.section_id,
+				   c.category_id,
+				   COALESCE(ct.name, c.name) as name
+			FROM public.store_section_categories as sc
+			INNER JOIN public.categories as c
+				ON c.category_id = sc.category_id
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.category_translations as t
+				WHERE t.category_id = c.category_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as ct ON true
+			WHERE sc.section_id = ANY($1::bigint[])
+			ORDER BY sc.section_id, name`
+
+	rows, err := s.db.Query(ctx, sql, sectionIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[store.SectionCategoryDTO])
+}
+
+func insertSections(ctx context.Context, tx pgx.Tx, storeID uint64, sections []store.Section) error {
+	for _, section := range sections {
+		sql := `INSERT INTO public.store_sections (store_id, name, position)
+				VALUES ($1, $2, $3)
+				RETURNING section_id`
+
+		var sectionID uint64
+		if err := tx.QueryRow(ctx, sql, storeID, section.Name, section.Position).Scan(&sectionID); err != nil {
+			return err
+		}
+
+		sql = `INSERT INTO public.store_section_categories (store_id, category_id, section_id)
+				VALUES ($1, $2, $3)`
+
+		for _, categoryID := range section.CategoryIDs {
+			if _, err := tx.Exec(ctx, sql, storeID, categoryID, sectionID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
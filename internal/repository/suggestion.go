@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/suggestion"
+	"time"
+)
+
+type SuggestionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSuggestionRepository(db *pgxpool.Pool) *SuggestionRepository {
+	return &SuggestionRepository{db: db}
+}
+
+// GetPurchases returns the days the group bought every product name since the day, the oldest first
+func (s *SuggestionRepository) GetPurchases(ctx context.Context, groupID uint64, since time.Time) ([]suggestion.Purchase, error) {
+	sql := `SELECT DISTINCT product_name_id, bought_at::date as day
+			FROM public.products
+			WHERE group_id = $1 AND status = 'closed' AND bought_at >= $2
+			ORDER BY product_name_id, day`
+
+	rows, err := s.db.Query(ctx, sql, groupID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var purchases []suggestion.Purchase
+	for rows.Next() {
+		var purchase suggestion.Purchase
+		if err = rows.Scan(&purchase.ProductNameID, &purchase.Day); err != nil {
+			return nil, err
+		}
+
+		purchases = append(purchases, purchase)
+	}
+
+	return purchases, rows.Err()
+}
+
+func (s *SuggestionRepository) GetOpenProductNameIds(ctx context.Context, groupID uint64) ([]uint64, error) {
+	sql := `SELECT DISTINCT product_name_id FROM public.products WHERE group_id = $1 AND status = 'open'`
+
+	rows, err := s.db.Query(ctx, sql, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[uint64])
+}
+
+func (s *SuggestionRepository) GetDismissals(ctx context.Context, groupID uint64) ([]suggestion.Dismissal, error) {
+	sql := `SELECT group_id, product_name_id, dismissed_by, dismissed_at
+			FROM public.suggestion_dismissals
+			WHERE group_id = $1`
+
+	rows, err := s.db.Query(ctx, sql, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dismissals []suggestion.Dismissal
+	for rows.Next() {
+		var dismissal suggestion.Dismissal
+		err = rows.Scan(
+			&dismissal.GroupID,
+			&dismissal.ProductNameID,
+			&dismissal.DismissedBy,
+			&dismissal.DismissedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		dismissals = append(dismissals, dismissal)
+	}
+
+	return dismissals, rows.Err()
+}
+
+// Dismiss saves the dismissal, dismissing the product name again moves it to the new time
+func (s *SuggestionRepository) Dismiss(ctx context.Context, dismissal suggestion.Dismissal) error {
+	sql := `INSERT INTO public.suggestion_dismissals (group_id, product_name_id, dismissed_by, dismissed_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (group_id, product_name_id) DO UPDATE
+			SET dismissed_by = EXCLUDED.dismissed_by, dismissed_at = EXCLUDED.dismissed_at`
+
+	_, err := s.db.Exec(ctx, sql,
+		dismissal.GroupID,
+		dismissal.ProductNameID,
+		dismissal.DismissedBy,
+		dismissal.DismissedAt)
+
+	return err
+}
+
+func (s *SuggestionRepository) GetProductNames(ctx context.Context, productNameIDs []uint64, locales []string) ([]suggestion.ProductNameDTO, error) {
+	sql := `SELECT pn.product_name_id, COALESCE(pnt.name, pn.name) as name
+			FROM public.product_names as pn
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.product_name_translations as t
+				WHERE t.product_name_id = pn.product_name_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as pnt ON true
+			WHERE pn.product_name_id = ANY($1::bigint[])`
+
+	rows, err := s.db.Query(ctx, sql, productNameIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[suggestion.ProductNameDTO])
+}
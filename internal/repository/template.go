@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/template"
+)
+
+type TemplateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTemplateRepository(db *pgxpool.Pool) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+func (t *TemplateRepository) Create(ctx context.Context, tmpl template.Template, items []template.Item) (uint64, error) {
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `INSERT INTO public.list_templates (name, group_id, user_id, created_by, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING template_id`
+
+	row := tx.QueryRow(ctx, sql, tmpl.Name, tmpl.GroupID, tmpl.UserID, tmpl.CreatedBy, tmpl.CreatedAt)
+
+	var templateID uint64
+	if err = row.Scan(&templateID); err != nil {
+		return 0, err
+	}
+
+	if err = insertTemplateItems(ctx, tx, templateID, items); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return templateID, nil
+}
+
+func (t *TemplateRepository) GetById(ctx context.Context, templateID uint64) (template.Template, error) {
+	sql := `SELECT template_id, name, group_id, user_id, created_by, created_at FROM public.list_templates WHERE template_id = $1`
+
+	row := t.db.QueryRow(ctx, sql, templateID)
+
+	var tmpl template.Template
+	err := row.Scan(
+		&tmpl.TemplateID,
+		&tmpl.Name,
+		&tmpl.GroupID,
+		&tmpl.UserID,
+		&tmpl.CreatedBy,
+		&tmpl.CreatedAt)
+
+	if err != nil {
+		return template.Template{}, err
+	}
+
+	return tmpl, nil
+}
+
+func (t *TemplateRepository) GetItems(ctx context.Context, templateID uint64) ([]template.Item, error) {
+	sql := `SELECT template_item_id, template_id, product_name_id, quantity, unit, note
+			FROM public.list_template_items
+			WHERE template_id = $1
+			ORDER BY template_item_id`
+
+	rows, err := t.db.Query(ctx, sql, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []template.Item
+	for rows.Next() {
+		var item template.Item
+		err = rows.Scan(
+			&item.TemplateItemID,
+			&item.TemplateID,
+			&item.ProductNameID,
+			&item.Quantity,
+			&item.Unit,
+			&item.Note)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// Update renames the template and replaces its items unless items is nil
+func (t *TemplateRepository) Update(ctx context.Context, tmpl template.Template, items []template.Item) error {
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `UPDATE public.list_templates SET name = $1 WHERE template_id = $2`
+
+	if _, err = tx.Exec(ctx, sql, tmpl.Name, tmpl.TemplateID); err != nil {
+		return err
+	}
+
+	if items != nil {
+		sql = `DELETE FROM public.list_template_items WHERE template_id = $1`
+
+		if _, err = tx.Exec(ctx, sql, tmpl.TemplateID); err != nil {
+			return err
+		}
+
+		if err = insertTemplateItems(ctx, tx, tmpl.TemplateID, items); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (t *TemplateRepository) Delete(ctx context.Context, templateID uint64) error {
+	sql := `DELETE FROM public.list_templates WHERE template_id = $1`
+
+	_, err := t.db.Exec(ctx, sql, templateID)
+
+	return err
+}
+
+// GetTemplates returns the templates of the group and the personal templates of the user, by name
+func (t *TemplateRepository) GetTemplates(ctx context.Context, groupID uint64, userID uint64) ([]template.TemplateDTO, error) {
+	sql := `SELECT template_id, name, user_id IS NOT NULL as personal, created_by, created_at
+			FROM public.list_templates
+			WHERE group_id = $1 OR user_id = $2
+			ORDER BY name, template_id`
+
+	rows, err := t.db.Query(ctx, sql, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[template.TemplateDTO])
+}
+
+func (t *TemplateRepository) GetTemplateItems(ctx context.Context, templateIDs []uint64, locales []string) ([]template.TemplateItemDTO, error) {
+	sql := `SELECT i.template_item_id,
+				   i.template_id,
+				   i.product_name_id,
+				   COALESCE(pnt.name, pn.name) as product_name,
+				   i.quantity,
+				   i.unit,
+				   i.note
+			FROM public.list_template_items as i
+			INNER JOIN public.product_names as pn
+				ON pn.product_name_id = i.product_name_id
+			LEFT JOIN LATERAL (
+				SELECT t.name FROM public.product_name_translations as t
+				WHERE t.product_name_id = pn.product_name_id AND t.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], t.locale)
+				LIMIT 1
+			) as pnt ON true
+			WHERE i.template_id = ANY($1::bigint[])
+			ORDER BY i.template_id, i.template_item_id`
+
+	rows, err := t.db.Query(ctx, sql, templateIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[template.TemplateItemDTO])
+}
+
+func insertTemplateItems(ctx context.Context, tx pgx.Tx, templateID uint64, items []template.Item) error {
+	sql := `INSERT INTO public.list_template_items (template_id, product_name_id, quantity, unit, note)
+			VALUES ($1, $2, $3, $4, $5)`
+
+	for _, item := range items {
+		if _, err := tx.Exec(ctx, sql, templateID, item.ProductNameID, item.Quantity, item.Unit, item.Note); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
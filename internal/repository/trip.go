@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tclutin/shoppinglist-api/internal/domain/trip"
+)
+
+type TripRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTripRepository(db *pgxpool.Pool) *TripRepository {
+	return &TripRepository{db: db}
+}
+
+// Create saves the trip and closes its products, pgx.ErrNoRows means one of the
+// products isn't open in the group of the trip
+func (t *TripRepository) Create(ctx context.Context, trip trip.Trip, items []trip.Item) (uint64, error) {
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `INSERT INTO public.trips (group_id, buyer_id, store, trip_date, total, currency, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING trip_id`
+
+	row := tx.QueryRow(ctx, sql, trip.GroupID, trip.BuyerID, trip.Store, trip.Date, trip.Total, trip.Currency, trip.CreatedAt)
+
+	var tripID uint64
+	if err = row.Scan(&tripID); err != nil {
+		return 0, err
+	}
+
+	sql = `UPDATE public.products
+			SET status = 'closed',
+			    price = COALESCE($1, price),
+			    currency = CASE WHEN $1::numeric IS NULL THEN currency ELSE $2 END,
+			    bought_by = $3,
+			    trip_id = $4,
+			    bought_at = $5
+			WHERE product_id = $6 AND group_id = $7 AND status = 'open'`
+
+	for _, item := range items {
+		tag, err := tx.Exec(ctx, sql, item.Price, trip.Currency, trip.BuyerID, tripID, trip.Date, item.ProductID, trip.GroupID)
+		if err != nil {
+			return 0, err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return 0, pgx.ErrNoRows
+		}
+
+		if err = recordPrice(ctx, tx, item.ProductID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return tripID, nil
+}
+
+func (t *TripRepository) GetTripsByGroupId(ctx context.Context, groupID uint64, limit int, offset int) ([]trip.TripDTO, error) {
+	sql := `SELECT t.trip_id,
+				   t.store,
+				   t.trip_date,
+				   t.total,
+				   COALESCE(t.currency, g.currency) as currency,
+				   t.buyer_id,
+				   u.username as buyer,
+				   t.created_at
+			FROM public.trips as t
+			INNER JOIN public.groups as g
+				ON g.group_id = t.group_id
+			INNER JOIN public.users as u
+				ON u.user_id = t.buyer_id
+			WHERE t.group_id = $1
+			ORDER BY t.trip_date DESC, t.trip_id DESC
+			LIMIT $2 OFFSET $3`
+
+	rows, err := t.db.Query(ctx, sql, groupID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	trips, err := pgx.CollectRows(rows, pgx.RowToStructByName[trip.TripDTO])
+	if err != nil {
+		return nil, err
+	}
+
+	return trips, nil
+}
+
+func (t *TripRepository) GetGroupTrip(ctx context.Context, groupID uint64, tripID uint64) (trip.TripDTO, error) {
+	sql := `SELECT t.trip_id,
+				   t.store,
+				   t.trip_date,
+				   t.total,
+				   COALESCE(t.currency, g.currency) as currency,
+				   t.buyer_id,
+				   u.username as buyer,
+				   t.created_at
+			FROM public.trips as t
+			INNER JOIN public.groups as g
+				ON g.group_id = t.group_id
+			INNER JOIN public.users as u
+				ON u.user_id = t.buyer_id
+			WHERE t.group_id = $1 AND t.trip_id = $2`
+
+	rows, err := t.db.Query(ctx, sql, groupID, tripID)
+	if err != nil {
+		return trip.TripDTO{}, err
+	}
+
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[trip.TripDTO])
+}
+
+func (t *TripRepository) GetTripItems(ctx context.Context, tripIDs []uint64, locales []string) ([]trip.TripItemDTO, error) {
+	sql := `SELECT p.trip_id,
+				   p.product_id,
+				   COALESCE(pnt.name, pn.name) as product_name,
+				   p.quantity,
+				   p.unit,
+				   p.price,
+				   COALESCE(p.currency, g.currency) as currency
+			FROM public.products as p
+			INNER JOIN public.groups as g
+				ON g.group_id = p.group_id
+			INNER JOIN public.product_names as pn
+				ON pn.product_name_id = p.product_name_id
+			LEFT JOIN LATERAL (
+				SELECT tr.name FROM public.product_name_translations as tr
+				WHERE tr.product_name_id = pn.product_name_id AND tr.locale = ANY($2::text[])
+				ORDER BY array_position($2::text[], tr.locale)
+				LIMIT 1
+			) as pnt ON true
+			WHERE p.trip_id = ANY($1)
+			ORDER BY p.trip_id, p.product_id`
+
+	rows, err := t.db.Query(ctx, sql, tripIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[trip.TripItemDTO])
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
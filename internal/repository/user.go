@@ -48,7 +48,9 @@ func (u *UserRepository) GetById(ctx context.Context, userID uint64) (user.User,
 		&usr.Username,
 		&usr.Password,
 		&usr.Gender,
-		&usr.CreatedAt)
+		&usr.CreatedAt,
+		&usr.IsAdmin,
+		&usr.Locale)
 
 	if err != nil {
 		return usr, err
@@ -68,7 +70,9 @@ func (u *UserRepository) GetByUsername(ctx context.Context, username string) (us
 		&usr.Username,
 		&usr.Password,
 		&usr.Gender,
-		&usr.CreatedAt)
+		&usr.CreatedAt,
+		&usr.IsAdmin,
+		&usr.Locale)
 
 	if err != nil {
 		return usr, err
@@ -77,8 +81,16 @@ func (u *UserRepository) GetByUsername(ctx context.Context, username string) (us
 	return usr, nil
 }
 
+func (u *UserRepository) UpdateLocale(ctx context.Context, userID uint64, locale string) error {
+	sql := `UPDATE public.users SET locale = $1 WHERE user_id = $2`
+
+	_, err := u.db.Exec(ctx, sql, locale, userID)
+
+	return err
+}
+
 func (u *UserRepository) GetGroupsByUserId(ctx context.Context, userId uint64) ([]group.GroupDTO, error) {
-	sql := `SELECT g.group_id, g.name, g.description, g.code FROM public.members as m
+	sql := `SELECT g.group_id, g.name, g.description, g.code, g.merge_duplicates, g.split_rule, g.currency FROM public.members as m
 			INNER JOIN public.groups as g ON g.group_id = m.group_id
 			WHERE m.user_id = $1`
 
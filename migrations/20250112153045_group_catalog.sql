@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.product_names
+    ADD COLUMN IF NOT EXISTS group_id BIGINT NULL,
+    ADD FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS product_names_group_id_name_idx
+    ON public.product_names (group_id, lower(name))
+    WHERE group_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS public.product_names_group_id_name_idx;
+ALTER TABLE public.product_names DROP COLUMN IF EXISTS group_id;
+-- +goose StatementEnd
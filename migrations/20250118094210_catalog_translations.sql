@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT 'ru';
+
+CREATE TABLE IF NOT EXISTS public.category_translations (
+    category_id BIGINT NOT NULL,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    PRIMARY KEY (category_id, locale),
+    FOREIGN KEY (category_id) REFERENCES public.categories (category_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS public.product_name_translations (
+    product_name_id BIGINT NOT NULL,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    PRIMARY KEY (product_name_id, locale),
+    FOREIGN KEY (product_name_id) REFERENCES public.product_names (product_name_id) ON DELETE CASCADE
+);
+
+INSERT INTO public.category_translations (category_id, locale, name)
+SELECT c.category_id, 'en', t.name
+FROM public.categories as c
+INNER JOIN (VALUES
+    ('молочные продукты', 'dairy products'),
+    ('мясные продукты', 'meat products'),
+    ('рыбные продукты', 'fish products'),
+    ('яйцо', 'eggs'),
+    ('масложировая продукция', 'oils and fats'),
+    ('хлебобулочные изделия', 'bakery products'),
+    ('кондитерские изделия', 'confectionery'),
+    ('продукты пчеловодства', 'bee products'),
+    ('бакалейные товары', 'groceries'),
+    ('безалкогольные напитки', 'soft drinks'),
+    ('алкогольные напитки', 'alcoholic drinks'),
+    ('табачные изделия', 'tobacco products'),
+    ('плодоовощная продукция', 'fruits and vegetables'),
+    ('прочие продовольственные товары', 'other food products')
+) as t (ru, name) ON t.ru = c.name
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.product_name_translations;
+DROP TABLE IF EXISTS public.category_translations;
+ALTER TABLE public.users DROP COLUMN IF EXISTS locale;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS product_names_name_trgm_idx
+    ON public.product_names USING gin (lower(name) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS product_name_translations_name_trgm_idx
+    ON public.product_name_translations USING gin (lower(name) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS products_product_name_id_group_id_idx
+    ON public.products (product_name_id, group_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS public.products_product_name_id_group_id_idx;
+DROP INDEX IF EXISTS public.product_name_translations_name_trgm_idx;
+DROP INDEX IF EXISTS public.product_names_name_trgm_idx;
+-- +goose StatementEnd
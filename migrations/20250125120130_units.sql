@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.products
+    ALTER COLUMN quantity TYPE NUMERIC(12, 3),
+    ADD COLUMN IF NOT EXISTS unit TEXT NOT NULL DEFAULT 'pcs'
+        CHECK (unit IN ('pcs', 'g', 'kg', 'ml', 'l', 'pack'));
+
+ALTER TABLE public.product_names
+    ADD COLUMN IF NOT EXISTS default_unit TEXT NOT NULL DEFAULT 'pcs'
+        CHECK (default_unit IN ('pcs', 'g', 'kg', 'ml', 'l', 'pack'));
+
+UPDATE public.product_names SET default_unit = 'l'
+WHERE group_id IS NULL AND name IN (
+    'молоко питьевое', 'сливки питьевые', 'кефир', 'ряженка', 'простокваша',
+    'масло подсолнечное', 'масло оливковое', 'вода', 'вода минеральная', 'квас',
+    'сок', 'лимонад', 'газировка', 'пиво', 'сидр');
+
+UPDATE public.product_names SET default_unit = 'kg'
+WHERE group_id IS NULL AND (category_id IN (
+    SELECT category_id FROM public.categories
+    WHERE name IN ('мясные продукты', 'рыбные продукты', 'плодоовощная продукция')) OR name IN (
+    'мука', 'рис', 'манка', 'пшено', 'гречка', 'горох', 'фасоль', 'сахар', 'соль', 'творог'))
+  AND name NOT IN (
+    'пельмени', 'колбаса вареная', 'колбаса копченая', 'сосиски', 'мясные консервы',
+    'икра', 'лимон', 'лайм', 'авокадо', 'ананас', 'тыква', 'кабачок', 'огурец', 'баклажан',
+    'капуста белокочанная', 'капуста краснокочанная', 'капуста цветная', 'брокколи',
+    'салат', 'укроп', 'петрушка', 'сельдерей', 'манго', 'гранат', 'грейпфрут');
+
+UPDATE public.product_names SET default_unit = 'pack'
+WHERE group_id IS NULL AND name IN (
+    'пельмени', 'сосиски', 'макароны', 'рожки', 'вермишель', 'печенье', 'чай зеленый',
+    'чай черный', 'кофе молотый', 'кофе растворимый', 'сигареты', 'чипсы');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.product_names DROP COLUMN IF EXISTS default_unit;
+
+ALTER TABLE public.products
+    DROP COLUMN IF EXISTS unit,
+    ALTER COLUMN quantity TYPE INT USING ceil(quantity)::int;
+-- +goose StatementEnd
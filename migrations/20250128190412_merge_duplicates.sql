@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.groups ADD COLUMN IF NOT EXISTS merge_duplicates BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS public.product_requests (
+    product_request_id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    quantity NUMERIC(12, 3) NOT NULL,
+    unit TEXT NOT NULL CHECK (unit IN ('pcs', 'g', 'kg', 'ml', 'l', 'pack')),
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    FOREIGN KEY (product_id) REFERENCES public.products (product_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS product_requests_product_id_idx ON public.product_requests (product_id);
+
+INSERT INTO public.product_requests (product_id, user_id, quantity, unit, created_at)
+SELECT product_id, added_by, quantity, unit, created_at FROM public.products;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.product_requests;
+ALTER TABLE public.groups DROP COLUMN IF EXISTS merge_duplicates;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.products ADD COLUMN IF NOT EXISTS note TEXT NOT NULL DEFAULT '';
+ALTER TABLE public.products ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'normal' CHECK (priority IN ('low', 'normal', 'urgent'));
+ALTER TABLE public.products ADD COLUMN IF NOT EXISTS needed_by DATE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.products DROP COLUMN IF EXISTS needed_by;
+ALTER TABLE public.products DROP COLUMN IF EXISTS priority;
+ALTER TABLE public.products DROP COLUMN IF EXISTS note;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.products ADD COLUMN IF NOT EXISTS assigned_to BIGINT REFERENCES public.users (user_id) ON DELETE SET NULL;
+ALTER TABLE public.products ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.products DROP COLUMN IF EXISTS claimed_at;
+ALTER TABLE public.products DROP COLUMN IF EXISTS assigned_to;
+-- +goose StatementEnd
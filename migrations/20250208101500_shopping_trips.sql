@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.trips (
+    trip_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    buyer_id BIGINT NOT NULL,
+    store TEXT NOT NULL DEFAULT '',
+    trip_date DATE NOT NULL,
+    total DECIMAL NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (buyer_id) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS trips_group_id_idx ON public.trips (group_id, trip_date);
+
+ALTER TABLE public.products ADD COLUMN IF NOT EXISTS trip_id BIGINT REFERENCES public.trips (trip_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS products_trip_id_idx ON public.products (trip_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.products DROP COLUMN IF EXISTS trip_id;
+DROP TABLE IF EXISTS public.trips;
+-- +goose StatementEnd
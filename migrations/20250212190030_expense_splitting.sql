@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.members ADD COLUMN IF NOT EXISTS share NUMERIC(6, 2) NOT NULL DEFAULT 1 CHECK (share >= 0);
+
+ALTER TABLE public.groups ADD COLUMN IF NOT EXISTS split_rule TEXT NOT NULL DEFAULT 'equal' CHECK (split_rule IN ('equal', 'share'));
+
+CREATE TABLE IF NOT EXISTS public.product_split_exclusions (
+    product_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    PRIMARY KEY (product_id, user_id),
+    FOREIGN KEY (product_id) REFERENCES public.products (product_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS public.settlements (
+    settlement_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    from_user_id BIGINT NOT NULL,
+    to_user_id BIGINT NOT NULL,
+    amount DECIMAL NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (from_user_id) REFERENCES public.users (user_id) ON DELETE CASCADE,
+    FOREIGN KEY (to_user_id) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS settlements_group_id_idx ON public.settlements (group_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.settlements;
+DROP TABLE IF EXISTS public.product_split_exclusions;
+ALTER TABLE public.groups DROP COLUMN IF EXISTS split_rule;
+ALTER TABLE public.members DROP COLUMN IF EXISTS share;
+-- +goose StatementEnd
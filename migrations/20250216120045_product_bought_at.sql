@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.products ADD COLUMN IF NOT EXISTS bought_at TIMESTAMP;
+
+UPDATE public.products as p
+SET bought_at = COALESCE((SELECT t.trip_date FROM public.trips as t WHERE t.trip_id = p.trip_id), p.created_at)
+WHERE p.status = 'closed';
+
+CREATE INDEX IF NOT EXISTS products_group_id_bought_at_idx ON public.products (group_id, bought_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.products DROP COLUMN IF EXISTS bought_at;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.product_prices (
+    product_price_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    product_name_id BIGINT NOT NULL,
+    product_id BIGINT UNIQUE,
+    quantity NUMERIC(12, 3) NOT NULL CHECK (quantity > 0),
+    unit TEXT NOT NULL CHECK (unit IN ('pcs', 'g', 'kg', 'ml', 'l', 'pack')),
+    price DECIMAL NOT NULL,
+    recorded_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_name_id) REFERENCES public.product_names (product_name_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES public.products (product_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS product_prices_group_id_product_name_id_idx ON public.product_prices (group_id, product_name_id, recorded_at);
+
+INSERT INTO public.product_prices (group_id, product_name_id, product_id, quantity, unit, price, recorded_at)
+SELECT group_id, product_name_id, product_id, quantity, unit, price, COALESCE(bought_at, created_at)
+FROM public.products
+WHERE status = 'closed' AND price IS NOT NULL AND quantity > 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.product_prices;
+-- +goose StatementEnd
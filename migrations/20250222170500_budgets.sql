@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.budgets (
+    budget_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    category_id BIGINT,
+    amount DECIMAL NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES public.categories (category_id) ON DELETE CASCADE
+);
+
+-- one budget for the whole group and one per category
+CREATE UNIQUE INDEX IF NOT EXISTS budgets_group_id_category_id_idx ON public.budgets (group_id, COALESCE(category_id, 0));
+
+CREATE TABLE IF NOT EXISTS public.budget_alerts (
+    budget_alert_id BIGSERIAL PRIMARY KEY,
+    budget_id BIGINT NOT NULL,
+    period_start DATE NOT NULL,
+    threshold INT NOT NULL,
+    spent DECIMAL NOT NULL,
+    amount DECIMAL NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    UNIQUE (budget_id, period_start, threshold),
+    FOREIGN KEY (budget_id) REFERENCES public.budgets (budget_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.budget_alerts;
+DROP TABLE IF EXISTS public.budgets;
+-- +goose StatementEnd
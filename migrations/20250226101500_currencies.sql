@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- rate is the price of one unit of the currency in rubles, admins keep the rates up to date
+CREATE TABLE IF NOT EXISTS public.exchange_rates (
+    currency TEXT PRIMARY KEY CHECK (currency ~ '^[A-Z]{3}$'),
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+INSERT INTO public.exchange_rates (currency, rate)
+VALUES ('RUB', 1), ('KZT', 0.19), ('USD', 100), ('EUR', 105)
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE public.groups ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'RUB' REFERENCES public.exchange_rates (currency);
+
+-- a price without a currency is in the currency of the group
+ALTER TABLE public.products ADD COLUMN IF NOT EXISTS currency TEXT REFERENCES public.exchange_rates (currency);
+ALTER TABLE public.trips ADD COLUMN IF NOT EXISTS currency TEXT REFERENCES public.exchange_rates (currency);
+
+ALTER TABLE public.settlements ADD COLUMN IF NOT EXISTS currency TEXT REFERENCES public.exchange_rates (currency);
+UPDATE public.settlements as s SET currency = g.currency FROM public.groups as g WHERE g.group_id = s.group_id;
+ALTER TABLE public.settlements ALTER COLUMN currency SET NOT NULL;
+
+ALTER TABLE public.product_prices ADD COLUMN IF NOT EXISTS currency TEXT REFERENCES public.exchange_rates (currency);
+UPDATE public.product_prices as pp SET currency = g.currency FROM public.groups as g WHERE g.group_id = pp.group_id;
+ALTER TABLE public.product_prices ALTER COLUMN currency SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.product_prices DROP COLUMN IF EXISTS currency;
+ALTER TABLE public.settlements DROP COLUMN IF EXISTS currency;
+ALTER TABLE public.trips DROP COLUMN IF EXISTS currency;
+ALTER TABLE public.products DROP COLUMN IF EXISTS currency;
+ALTER TABLE public.groups DROP COLUMN IF EXISTS currency;
+DROP TABLE IF EXISTS public.exchange_rates;
+-- +goose StatementEnd
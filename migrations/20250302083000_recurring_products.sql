@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a rule puts the product on the list again: cron is a standard five field expression in UTC,
+-- every_days repeats the product that many days after the last one is bought
+CREATE TABLE IF NOT EXISTS public.recurring_products (
+    recurring_product_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    product_name_id BIGINT NOT NULL,
+    quantity NUMERIC(12, 3) NOT NULL CHECK (quantity > 0),
+    unit TEXT NOT NULL CHECK (unit IN ('pcs', 'g', 'kg', 'ml', 'l', 'pack')),
+    note TEXT NOT NULL DEFAULT '',
+    cron TEXT,
+    every_days INT CHECK (every_days > 0),
+    -- the product the rule has put on the list last
+    product_id BIGINT,
+    next_run_at TIMESTAMP,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    CHECK ((cron IS NULL) <> (every_days IS NULL)),
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_name_id) REFERENCES public.product_names (product_name_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES public.products (product_id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS recurring_products_group_id_idx ON public.recurring_products (group_id);
+CREATE INDEX IF NOT EXISTS recurring_products_next_run_at_idx ON public.recurring_products (next_run_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.recurring_products;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a template belongs either to a group or to one user, who can apply it in any of their groups
+CREATE TABLE IF NOT EXISTS public.list_templates (
+    template_id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    group_id BIGINT,
+    user_id BIGINT,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    CHECK ((group_id IS NULL) <> (user_id IS NULL)),
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES public.users (user_id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS list_templates_group_id_idx ON public.list_templates (group_id);
+CREATE INDEX IF NOT EXISTS list_templates_user_id_idx ON public.list_templates (user_id);
+
+CREATE TABLE IF NOT EXISTS public.list_template_items (
+    template_item_id BIGSERIAL PRIMARY KEY,
+    template_id BIGINT NOT NULL,
+    product_name_id BIGINT NOT NULL,
+    quantity NUMERIC(12, 3) NOT NULL CHECK (quantity > 0),
+    unit TEXT NOT NULL CHECK (unit IN ('pcs', 'g', 'kg', 'ml', 'l', 'pack')),
+    note TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (template_id) REFERENCES public.list_templates (template_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_name_id) REFERENCES public.product_names (product_name_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS list_template_items_template_id_idx ON public.list_template_items (template_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.list_template_items;
+DROP TABLE IF EXISTS public.list_templates;
+-- +goose StatementEnd
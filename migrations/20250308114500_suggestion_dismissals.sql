@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a dismissed product name isn't suggested to the group until it is bought again
+CREATE TABLE IF NOT EXISTS public.suggestion_dismissals (
+    group_id BIGINT NOT NULL,
+    product_name_id BIGINT NOT NULL,
+    dismissed_by BIGINT NOT NULL,
+    dismissed_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY (group_id, product_name_id),
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_name_id) REFERENCES public.product_names (product_name_id) ON DELETE CASCADE,
+    FOREIGN KEY (dismissed_by) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.suggestion_dismissals;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the stock of a product name the group has at home, quantities in compatible units are kept in one row;
+-- expires_at is the earliest expiry date of the stock
+CREATE TABLE IF NOT EXISTS public.pantry_items (
+    pantry_item_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    product_name_id BIGINT NOT NULL,
+    quantity NUMERIC(12, 3) NOT NULL CHECK (quantity >= 0),
+    unit TEXT NOT NULL CHECK (unit IN ('pcs', 'g', 'kg', 'ml', 'l', 'pack')),
+    expires_at DATE,
+    -- restock_quantity of the product is put on the list when the stock drops to the threshold
+    low_stock_threshold NUMERIC(12, 3) CHECK (low_stock_threshold >= 0),
+    restock_quantity NUMERIC(12, 3) CHECK (restock_quantity > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    UNIQUE (group_id, product_name_id, unit),
+    CHECK ((low_stock_threshold IS NULL) = (restock_quantity IS NULL)),
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_name_id) REFERENCES public.product_names (product_name_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS pantry_items_group_id_expires_at_idx ON public.pantry_items (group_id, expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.pantry_items;
+-- +goose StatementEnd
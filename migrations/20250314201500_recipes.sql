@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.recipes (
+    recipe_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    servings INT NOT NULL CHECK (servings > 0),
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS recipes_group_id_idx ON public.recipes (group_id);
+
+-- quantities of the ingredients are for the servings of the recipe
+CREATE TABLE IF NOT EXISTS public.recipe_ingredients (
+    ingredient_id BIGSERIAL PRIMARY KEY,
+    recipe_id BIGINT NOT NULL,
+    product_name_id BIGINT NOT NULL,
+    quantity NUMERIC(12, 3) NOT NULL CHECK (quantity > 0),
+    unit TEXT NOT NULL CHECK (unit IN ('pcs', 'g', 'kg', 'ml', 'l', 'pack')),
+    note TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (recipe_id) REFERENCES public.recipes (recipe_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_name_id) REFERENCES public.product_names (product_name_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS recipe_ingredients_recipe_id_idx ON public.recipe_ingredients (recipe_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.recipe_ingredients;
+DROP TABLE IF EXISTS public.recipes;
+-- +goose StatementEnd
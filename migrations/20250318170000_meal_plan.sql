@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- an entry of the meal plan is a recipe, cooked for servings or the servings of the recipe, or free text
+CREATE TABLE IF NOT EXISTS public.meal_plan_entries (
+    entry_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    plan_date DATE NOT NULL,
+    slot TEXT NOT NULL CHECK (slot IN ('breakfast', 'lunch', 'dinner', 'snack')),
+    recipe_id BIGINT,
+    servings INT CHECK (servings > 0),
+    text TEXT NOT NULL DEFAULT '',
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    CHECK (recipe_id IS NOT NULL OR text <> ''),
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (recipe_id) REFERENCES public.recipes (recipe_id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS meal_plan_entries_group_id_plan_date_idx ON public.meal_plan_entries (group_id, plan_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.meal_plan_entries;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.stores (
+    store_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES public.users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS stores_group_id_idx ON public.stores (group_id);
+
+-- sections are walked through in the order of position
+CREATE TABLE IF NOT EXISTS public.store_sections (
+    section_id BIGSERIAL PRIMARY KEY,
+    store_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    position INT NOT NULL,
+    UNIQUE (store_id, position),
+    FOREIGN KEY (store_id) REFERENCES public.stores (store_id) ON DELETE CASCADE
+);
+
+-- a category is in one section of a store
+CREATE TABLE IF NOT EXISTS public.store_section_categories (
+    store_id BIGINT NOT NULL,
+    category_id BIGINT NOT NULL,
+    section_id BIGINT NOT NULL,
+    PRIMARY KEY (store_id, category_id),
+    FOREIGN KEY (store_id) REFERENCES public.stores (store_id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES public.categories (category_id) ON DELETE CASCADE,
+    FOREIGN KEY (section_id) REFERENCES public.store_sections (section_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.store_section_categories;
+DROP TABLE IF EXISTS public.store_sections;
+DROP TABLE IF EXISTS public.stores;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- rank is the order of the products of a group picked by hand, a new product gets the rank
+-- after the last one of its group, a moved product takes the middle of its new neighbours
+-- and numeric keeps the halves exact, so nothing is renumbered
+ALTER TABLE public.products ADD COLUMN IF NOT EXISTS rank NUMERIC;
+
+UPDATE public.products as p SET rank = r.rank
+FROM (
+    SELECT product_id, row_number() OVER (PARTITION BY group_id ORDER BY created_at, product_id) as rank
+    FROM public.products
+) as r
+WHERE r.product_id = p.product_id;
+
+ALTER TABLE public.products ALTER COLUMN rank SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS products_group_id_rank_idx ON public.products (group_id, rank, product_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS public.products_group_id_rank_idx;
+ALTER TABLE public.products DROP COLUMN IF EXISTS rank;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- codes are kept in the EAN-13 form, a barcode learned by a group wins over the global one
+CREATE TABLE IF NOT EXISTS public.barcodes (
+    barcode_id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL CHECK (code ~ '^[0-9]{13}$'),
+    product_name_id BIGINT NOT NULL,
+    group_id BIGINT NULL,
+    created_by BIGINT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    FOREIGN KEY (product_name_id) REFERENCES public.product_names (product_name_id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES public.groups (group_id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES public.users (user_id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS barcodes_code_idx
+    ON public.barcodes (code)
+    WHERE group_id IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS barcodes_group_id_code_idx
+    ON public.barcodes (group_id, code)
+    WHERE group_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.barcodes;
+-- +goose StatementEnd
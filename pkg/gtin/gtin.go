@@ -0,0 +1,45 @@
+package gtin
+
+import "strings"
+
+// Normalize returns the EAN-13 form of an EAN-13 or UPC-A code, a UPC-A code is an
+// EAN-13 code with a leading zero. It reports false for other lengths and a wrong check digit
+func Normalize(code string) (string, bool) {
+	code = strings.TrimSpace(code)
+
+	if len(code) == 12 {
+		code = "0" + code
+	}
+
+	if len(code) != 13 {
+		return "", false
+	}
+
+	for _, digit := range code {
+		if digit < '0' || digit > '9' {
+			return "", false
+		}
+	}
+
+	if checkDigit(code[:12]) != code[12] {
+		return "", false
+	}
+
+	return code, true
+}
+
+// checkDigit weights the digits by 3 and 1 starting from the right, the check digit
+// brings the sum up to a multiple of ten
+func checkDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		weight := 1
+		if (len(digits)-1-i)%2 == 0 {
+			weight = 3
+		}
+
+		sum += int(digits[i]-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,52 @@
+package gtin
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+		ok   bool
+	}{
+		{name: "ean-13", code: "4006381333931", want: "4006381333931", ok: true},
+		{name: "ean-13 check digit zero", code: "4600682000020", want: "4600682000020", ok: true},
+		{name: "upc-a padded", code: "036000291452", want: "0036000291452", ok: true},
+		{name: "spaces trimmed", code: " 5901234123457\n", want: "5901234123457", ok: true},
+		{name: "wrong check digit", code: "4006381333932", ok: false},
+		{name: "upc-a wrong check digit", code: "036000291453", ok: false},
+		{name: "swapped digits", code: "4003681333931", ok: false},
+		{name: "ean-8", code: "96385074", ok: false},
+		{name: "too long", code: "40063813339310", ok: false},
+		{name: "letters", code: "40063813339a1", ok: false},
+		{name: "inner space", code: "400638 333931", ok: false},
+		{name: "empty", code: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Normalize(tt.code)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Normalize(%q) = %q, %v, want %q, %v", tt.code, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{digits: "400638133393", want: '1'},
+		{digits: "003600029145", want: '2'},
+		{digits: "590123412345", want: '7'},
+		{digits: "000000000000", want: '0'},
+	}
+
+	for _, tt := range tests {
+		if got := checkDigit(tt.digits); got != tt.want {
+			t.Errorf("checkDigit(%q) = %q, want %q", tt.digits, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package lineparser
+
+import (
+	"github.com/tclutin/shoppinglist-api/pkg/unit"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var unitAliases = map[string]string{
+	"шт": unit.Pieces, "штук": unit.Pieces, "штуки": unit.Pieces, "штука": unit.Pieces,
+	"pc": unit.Pieces, "pcs": unit.Pieces, "piece": unit.Pieces, "pieces": unit.Pieces,
+	"г": unit.Gram, "гр": unit.Gram, "грамм": unit.Gram, "грамма": unit.Gram, "граммов": unit.Gram,
+	"g": unit.Gram, "gr": unit.Gram, "gram": unit.Gram, "grams": unit.Gram,
+	"кг": unit.Kilo, "килограмм": unit.Kilo, "килограмма": unit.Kilo, "кило": unit.Kilo,
+	"kg": unit.Kilo, "kilo": unit.Kilo, "kilos": unit.Kilo,
+	"мл": unit.Milli, "ml": unit.Milli,
+	"л": unit.Liter, "литр": unit.Liter, "литра": unit.Liter, "литров": unit.Liter,
+	"l": unit.Liter, "liter": unit.Liter, "liters": unit.Liter, "litre": unit.Liter, "litres": unit.Liter,
+	"уп": unit.Pack, "упак": unit.Pack, "упаковка": unit.Pack, "упаковки": unit.Pack, "упаковок": unit.Pack,
+	"pack": unit.Pack, "packs": unit.Pack, "pkg": unit.Pack,
+}
+
+var (
+	leadingRegexp  *regexp.Regexp
+	trailingRegexp *regexp.Regexp
+)
+
+func init() {
+	aliases := make([]string, 0, len(unitAliases))
+	for alias := range unitAliases {
+		aliases = append(aliases, regexp.QuoteMeta(alias))
+	}
+
+	// longer aliases go first so that "кг" isn't matched as "к"
+	sort.Slice(aliases, func(i, j int) bool {
+		return len(aliases[i]) > len(aliases[j])
+	})
+
+	units := strings.Join(aliases, "|")
+	number := `(\d+(?:[.,]\d+)?)`
+
+	leadingRegexp = regexp.MustCompile(`^` + number + `\s*(?:(` + units + `)\.?)?\s+(.+)$`)
+	trailingRegexp = regexp.MustCompile(`^(.+?)\s*(?:\s|[xх×*])\s*` + number + `\s*(?:(` + units + `)\.?)?$`)
+}
+
+type Line struct {
+	Quantity float64
+	Unit     string
+	Name     string
+}
+
+// Parse splits a line like "2 кг картошки", "молоко x3" or "eggs 10 pcs" into
+// quantity, unit and product name. A line without a number is one piece of the product
+func Parse(text string) Line {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+
+	if match := leadingRegexp.FindStringSubmatch(text); match != nil {
+		if quantity, ok := parseNumber(match[1]); ok {
+			return newLine(quantity, match[2], match[3])
+		}
+	}
+
+	if match := trailingRegexp.FindStringSubmatch(text); match != nil {
+		if quantity, ok := parseNumber(match[2]); ok {
+			return newLine(quantity, match[3], match[1])
+		}
+	}
+
+	return Line{Quantity: 1, Name: text}
+}
+
+// Split returns the non-empty lines of a pasted text, one product per line or per ';'
+func Split(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == '\n' || r == '\r' || r == ';'
+	})
+
+	lines := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.Trim(strings.TrimSpace(field), "-•*,")
+		if field = strings.TrimSpace(field); field != "" {
+			lines = append(lines, field)
+		}
+	}
+
+	return lines
+}
+
+func newLine(quantity float64, alias string, name string) Line {
+	return Line{
+		Quantity: quantity,
+		Unit:     unitAliases[alias],
+		Name:     strings.TrimPrefix(strings.TrimSpace(name), "of "),
+	}
+}
+
+func parseNumber(text string) (float64, bool) {
+	quantity, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", "."), 64)
+	if err != nil || quantity <= 0 {
+		return 0, false
+	}
+
+	return quantity, true
+}
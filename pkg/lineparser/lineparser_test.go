@@ -0,0 +1,55 @@
+package lineparser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want Line
+	}{
+		{text: "2 кг картошки", want: Line{Quantity: 2, Unit: "kg", Name: "картошки"}},
+		{text: "2кг картошки", want: Line{Quantity: 2, Unit: "kg", Name: "картошки"}},
+		{text: "1,5 л молока", want: Line{Quantity: 1.5, Unit: "l", Name: "молока"}},
+		{text: "500 гр. сыра", want: Line{Quantity: 500, Unit: "g", Name: "сыра"}},
+		{text: "3 яблока", want: Line{Quantity: 3, Name: "яблока"}},
+		{text: "молоко x3", want: Line{Quantity: 3, Name: "молоко"}},
+		{text: "молоко х2", want: Line{Quantity: 2, Name: "молоко"}},
+		{text: "eggs 10 pcs", want: Line{Quantity: 10, Unit: "pcs", Name: "eggs"}},
+		{text: "flour 1.5kg", want: Line{Quantity: 1.5, Unit: "kg", Name: "flour"}},
+		{text: "2 packs of pasta", want: Line{Quantity: 2, Unit: "pack", Name: "pasta"}},
+		{text: "  Хлеб   Бородинский ", want: Line{Quantity: 1, Name: "хлеб бородинский"}},
+		{text: "0 кг соли", want: Line{Quantity: 1, Name: "0 кг соли"}},
+		{text: "кефир", want: Line{Quantity: 1, Name: "кефир"}},
+	}
+
+	for _, tt := range tests {
+		if got := Parse(tt.text); got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "lines", text: "молоко\nхлеб\r\nсыр", want: []string{"молоко", "хлеб", "сыр"}},
+		{name: "semicolons", text: "молоко; хлеб;", want: []string{"молоко", "хлеб"}},
+		{name: "bullets", text: "- молоко\n• хлеб\n* сыр,", want: []string{"молоко", "хлеб", "сыр"}},
+		{name: "blank lines", text: "\n  \n-\nмолоко\n\n", want: []string{"молоко"}},
+		{name: "empty", text: "", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Split(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
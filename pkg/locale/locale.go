@@ -0,0 +1,81 @@
+package locale
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var localeRegexp = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// Normalize returns the lowercase primary language subtag, e.g. "en" for "en-US"
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+
+	if i := strings.IndexAny(tag, "-_"); i != -1 {
+		tag = tag[:i]
+	}
+
+	return tag
+}
+
+func IsValid(locale string) bool {
+	return localeRegexp.MatchString(locale)
+}
+
+// ParseAcceptLanguage returns the locales of the Accept-Language header ordered by quality
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+
+	var parsed []weighted
+	seen := make(map[string]int)
+
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+
+		locale := Normalize(params[0])
+		if !IsValid(locale) {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err == nil {
+				quality = q
+			}
+		}
+
+		if quality <= 0 {
+			continue
+		}
+
+		if i, ok := seen[locale]; ok {
+			parsed[i].quality = max(parsed[i].quality, quality)
+			continue
+		}
+		seen[locale] = len(parsed)
+
+		parsed = append(parsed, weighted{locale: locale, quality: quality})
+	}
+
+	sort.SliceStable(parsed, func(i, j int) bool {
+		return parsed[i].quality > parsed[j].quality
+	})
+
+	locales := make([]string, 0, len(parsed))
+	for _, w := range parsed {
+		locales = append(locales, w.locale)
+	}
+
+	return locales
+}
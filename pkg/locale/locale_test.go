@@ -0,0 +1,72 @@
+package locale
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "en", want: "en"},
+		{tag: "en-US", want: "en"},
+		{tag: "ru_RU", want: "ru"},
+		{tag: " DE-at ", want: "de"},
+		{tag: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.tag); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   bool
+	}{
+		{locale: "en", want: true},
+		{locale: "fil", want: true},
+		{locale: "e", want: false},
+		{locale: "engl", want: false},
+		{locale: "EN", want: false},
+		{locale: "en-US", want: false},
+		{locale: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValid(tt.locale); got != tt.want {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{name: "empty", header: "", want: []string{}},
+		{name: "single", header: "ru-RU", want: []string{"ru"}},
+		{name: "header order without weights", header: "en, ru", want: []string{"en", "ru"}},
+		{name: "by quality", header: "ru;q=0.5, en;q=0.9, de", want: []string{"de", "en", "ru"}},
+		{name: "equal quality keeps order", header: "fr;q=0.7, it;q=0.7", want: []string{"fr", "it"}},
+		{name: "regions merged with best quality", header: "en-GB;q=0.3, ru;q=0.5, en-US;q=0.8", want: []string{"en", "ru"}},
+		{name: "zero quality dropped", header: "en;q=0, ru", want: []string{"ru"}},
+		{name: "wildcard and junk dropped", header: "*, 12, ru", want: []string{"ru"}},
+		{name: "bad quality counts as one", header: "ru;q=0.5, en;q=x", want: []string{"en", "ru"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseAcceptLanguage(tt.header); !slices.Equal(got, tt.want) {
+				t.Errorf("ParseAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
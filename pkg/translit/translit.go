@@ -0,0 +1,77 @@
+package translit
+
+import "strings"
+
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// latinToCyrillic is ordered so that the longest combinations are replaced first
+var latinToCyrillic = []struct {
+	latin    string
+	cyrillic string
+}{
+	{"shch", "щ"}, {"sch", "щ"},
+	{"zh", "ж"}, {"kh", "х"}, {"ts", "ц"}, {"ch", "ч"}, {"sh", "ш"},
+	{"yu", "ю"}, {"ya", "я"}, {"yo", "ё"}, {"ye", "е"},
+	{"a", "а"}, {"b", "б"}, {"c", "к"}, {"d", "д"}, {"e", "е"}, {"f", "ф"},
+	{"g", "г"}, {"h", "х"}, {"i", "и"}, {"j", "й"}, {"k", "к"}, {"l", "л"},
+	{"m", "м"}, {"n", "н"}, {"o", "о"}, {"p", "п"}, {"q", "к"}, {"r", "р"},
+	{"s", "с"}, {"t", "т"}, {"u", "у"}, {"v", "в"}, {"w", "в"}, {"x", "кс"},
+	{"y", "ы"}, {"z", "з"},
+}
+
+// ToLatin transliterates cyrillic letters of the lowercase text to latin ones
+func ToLatin(text string) string {
+	var builder strings.Builder
+
+	for _, r := range text {
+		if latin, ok := cyrillicToLatin[r]; ok {
+			builder.WriteString(latin)
+			continue
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// ToCyrillic transliterates latin letters of the lowercase text to cyrillic ones
+func ToCyrillic(text string) string {
+	var builder strings.Builder
+
+	var prev byte
+	for len(text) > 0 {
+		replaced := false
+
+		for _, pair := range latinToCyrillic {
+			if strings.HasPrefix(text, pair.latin) {
+				cyrillic := pair.cyrillic
+
+				// a y after a vowel is й like in "chay" or "zheltyy"
+				if pair.latin == "y" && strings.IndexByte("aeiouy", prev) != -1 {
+					cyrillic = "й"
+				}
+
+				builder.WriteString(cyrillic)
+				prev = text[len(pair.latin)-1]
+				text = text[len(pair.latin):]
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			builder.WriteByte(text[0])
+			prev = text[0]
+			text = text[1:]
+		}
+	}
+
+	return builder.String()
+}
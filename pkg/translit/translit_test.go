@@ -0,0 +1,62 @@
+package translit
+
+import "testing"
+
+func TestToLatin(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "молоко", want: "moloko"},
+		{text: "щавель", want: "shchavel"},
+		{text: "жёлтый сыр", want: "zheltyy syr"},
+		{text: "хлеб чёрный", want: "khleb chernyy"},
+		{text: "подъезд", want: "podezd"},
+		{text: "юля и яна", want: "yulya i yana"},
+		{text: "milk 2%", want: "milk 2%"},
+		{text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := ToLatin(tt.text); got != tt.want {
+			t.Errorf("ToLatin(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestToCyrillic(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "moloko", want: "молоко"},
+		{text: "shchi", want: "щи"},
+		{text: "borsch", want: "борщ"},
+		{text: "zhurnal", want: "журнал"},
+		{text: "kholodets", want: "холодец"},
+		{text: "chay", want: "чай"},
+		{text: "zheltyy", want: "желтый"},
+		{text: "myaso", want: "мясо"},
+		{text: "yogurt", want: "ёгурт"},
+		{text: "cola", want: "кола"},
+		{text: "xerox", want: "ксерокс"},
+		{text: "syr 200g", want: "сыр 200г"},
+		{text: "сыр", want: "сыр"},
+		{text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := ToCyrillic(tt.text); got != tt.want {
+			t.Errorf("ToCyrillic(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// TestRoundTrip takes words without "йо" and "ё", which come back as "ё" and "е"
+func TestRoundTrip(t *testing.T) {
+	for _, text := range []string{"молоко", "шоколад", "чай", "сахар", "жир", "сырой", "желтый"} {
+		if got := ToCyrillic(ToLatin(text)); got != text {
+			t.Errorf("ToCyrillic(ToLatin(%q)) = %q", text, got)
+		}
+	}
+}
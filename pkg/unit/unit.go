@@ -0,0 +1,56 @@
+package unit
+
+const (
+	Pieces = "pcs"
+	Gram   = "g"
+	Kilo   = "kg"
+	Milli  = "ml"
+	Liter  = "l"
+	Pack   = "pack"
+)
+
+// base maps a unit to the unit it can be converted to and the factor of conversion
+var base = map[string]struct {
+	unit   string
+	factor float64
+}{
+	Pieces: {Pieces, 1},
+	Gram:   {Gram, 1},
+	Kilo:   {Gram, 1000},
+	Milli:  {Milli, 1},
+	Liter:  {Milli, 1000},
+	Pack:   {Pack, 1},
+}
+
+func All() []string {
+	return []string{Pieces, Gram, Kilo, Milli, Liter, Pack}
+}
+
+func IsValid(unit string) bool {
+	_, ok := base[unit]
+	return ok
+}
+
+// Compatible reports whether quantities of both units can be summed, e.g. "g" and "kg"
+func Compatible(from string, to string) bool {
+	fromBase, ok := base[from]
+	if !ok {
+		return false
+	}
+
+	toBase, ok := base[to]
+	if !ok {
+		return false
+	}
+
+	return fromBase.unit == toBase.unit
+}
+
+// Convert converts the quantity between compatible units, e.g. 1.5 "kg" to 1500 "g"
+func Convert(quantity float64, from string, to string) (float64, bool) {
+	if !Compatible(from, to) {
+		return 0, false
+	}
+
+	return quantity * base[from].factor / base[to].factor, true
+}
@@ -0,0 +1,71 @@
+package unit
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIsValid(t *testing.T) {
+	for _, unit := range All() {
+		if !IsValid(unit) {
+			t.Errorf("IsValid(%q) = false for a unit of All", unit)
+		}
+	}
+
+	for _, unit := range []string{"", "kilo", "KG", "шт"} {
+		if IsValid(unit) {
+			t.Errorf("IsValid(%q) = true", unit)
+		}
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{from: Gram, to: Kilo, want: true},
+		{from: Kilo, to: Gram, want: true},
+		{from: Milli, to: Liter, want: true},
+		{from: Pieces, to: Pieces, want: true},
+		{from: Pack, to: Pack, want: true},
+		{from: Kilo, to: Liter, want: false},
+		{from: Pieces, to: Pack, want: false},
+		{from: Gram, to: Pieces, want: false},
+		{from: "box", to: "box", want: false},
+		{from: Gram, to: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := Compatible(tt.from, tt.to); got != tt.want {
+			t.Errorf("Compatible(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		from     string
+		to       string
+		want     float64
+		ok       bool
+	}{
+		{quantity: 1.5, from: Kilo, to: Gram, want: 1500, ok: true},
+		{quantity: 250, from: Gram, to: Kilo, want: 0.25, ok: true},
+		{quantity: 0.3, from: Liter, to: Milli, want: 300, ok: true},
+		{quantity: 750, from: Milli, to: Liter, want: 0.75, ok: true},
+		{quantity: 2, from: Kilo, to: Kilo, want: 2, ok: true},
+		{quantity: 3, from: Pieces, to: Pieces, want: 3, ok: true},
+		{quantity: 1, from: Kilo, to: Liter, ok: false},
+		{quantity: 1, from: Pack, to: Pieces, ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Convert(tt.quantity, tt.from, tt.to)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Convert(%v, %q, %q) = %v, %v, want %v, %v", tt.quantity, tt.from, tt.to, got, ok, tt.want, tt.ok)
+		}
+	}
+}